@@ -8,15 +8,23 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/solarisdb/perftests/pkg/server/configs"
 	"github.com/spf13/cobra"
 )
 
 var generateCfgCmd = &cobra.Command{
-	Use:   "generateCfg [filename | - | auto, op type[sleep|append|cleanup], op params] ",
+	Use:   "generateCfg [filename | - | auto, op type[sleep|append|cleanup|seq_query|rand_query|logs_meta], op params] ",
 	Short: "Creates the config: perftests generateCfg perftests.yaml append",
-	Args:  cobra.MinimumNArgs(2),
+	Long: `Creates the config: perftests generateCfg perftests.yaml append
+
+The append, seq_query and rand_query params may be followed by keep=<dataset>
+to keep the filled logs tagged by the dataset name. The seq_query and
+rand_query params may be followed by reuse=<dataset> to read the logs of the
+dataset instead of filling new ones. The cleanup op accepts the dataset name
+to delete its logs.`,
+	Args: cobra.MinimumNArgs(2),
 	RunE: func(c *cobra.Command, args []string) error {
 		configOutFile := args[0]
 		opType := args[1]
@@ -32,60 +40,105 @@ var generateCfgCmd = &cobra.Command{
 			writers, _ := strconv.Atoi(args[4])
 			batch, _ := strconv.Atoi(args[5])
 			msg, _ := strconv.Atoi(args[6])
+			ds, err := datasetArg(args[7:], false)
+			if err != nil {
+				return err
+			}
 			cfg = server.BuildConfig(server.Append, &server.AppendCfg{
 				ConcurrentLogs:   concLogs,
 				LogSize:          logSize,
 				WritersForOneLog: writers,
 				BatchSize:        batch,
 				MsgSize:          msg,
+				Dataset:          ds,
 			})
-			autoFileName = fmt.Sprintf("test-scripts/append_%s_logs_by_%s_size_%s_writers_batch_%s_by_%s.yaml",
+			autoFileName = fmt.Sprintf("test-scripts/append_%s_logs_by_%s_size_%s_writers_batch_%s_by_%s%s.yaml",
 				utils.HumanReadableSizePrecision(float64(concLogs), 0),
 				utils.HumanReadableBytesPrecision(float64(logSize), 0),
 				utils.HumanReadableSizePrecision(float64(writers), 0),
 				utils.HumanReadableSizePrecision(float64(batch), 0),
-				utils.HumanReadableBytesPrecision(float64(msg), 0))
+				utils.HumanReadableBytesPrecision(float64(msg), 0),
+				datasetFileSuffix(ds))
 		case "cleanup":
 			autoFileName = "test-scripts/cleanup.yaml"
-			cfg = server.BuildConfig(server.Cleanup, nil)
+			var dataset string
+			if len(args) > 2 {
+				dataset = args[2]
+				autoFileName = fmt.Sprintf("test-scripts/cleanup_%s.yaml", dataset)
+			}
+			cfg = server.BuildConfig(server.Cleanup, &server.CleanupCfg{Dataset: dataset})
 		case "seq_query":
 			concLogs, _ := strconv.Atoi(args[2])
 			logSize, _ := strconv.Atoi(args[3])
 			readers, _ := strconv.Atoi(args[4])
 			queryStep, _ := strconv.Atoi(args[5])
 			msg, _ := strconv.Atoi(args[6])
+			ds, err := datasetArg(args[7:], true)
+			if err != nil {
+				return err
+			}
 			cfg = server.BuildConfig(server.SeqQuery, &server.QueryCfg{
 				ConcurrentLogs:    concLogs,
 				LogSize:           logSize,
 				ReadersFromOneLog: readers,
 				QueryStep:         queryStep,
 				MsgSize:           msg,
+				Dataset:           ds,
 			})
-			autoFileName = fmt.Sprintf("test-scripts/seq_query_%s_logs_by_%s_size_%s_readers_batch_%s_by_%s.yaml",
+			autoFileName = fmt.Sprintf("test-scripts/seq_query_%s_logs_by_%s_size_%s_readers_batch_%s_by_%s%s.yaml",
 				utils.HumanReadableSizePrecision(float64(concLogs), 0),
 				utils.HumanReadableBytesPrecision(float64(logSize), 0),
 				utils.HumanReadableSizePrecision(float64(readers), 0),
 				utils.HumanReadableSizePrecision(float64(queryStep), 0),
-				utils.HumanReadableBytesPrecision(float64(msg), 0))
+				utils.HumanReadableBytesPrecision(float64(msg), 0),
+				datasetFileSuffix(ds))
 		case "rand_query":
 			concLogs, _ := strconv.Atoi(args[2])
 			logSize, _ := strconv.Atoi(args[3])
 			readers, _ := strconv.Atoi(args[4])
 			queryStep, _ := strconv.Atoi(args[5])
 			msg, _ := strconv.Atoi(args[6])
+			ds, err := datasetArg(args[7:], true)
+			if err != nil {
+				return err
+			}
 			cfg = server.BuildConfig(server.RandQuery, &server.QueryCfg{
 				ConcurrentLogs:    concLogs,
 				LogSize:           logSize,
 				ReadersFromOneLog: readers,
 				QueryStep:         queryStep,
 				MsgSize:           msg,
+				Dataset:           ds,
 			})
-			autoFileName = fmt.Sprintf("test-scripts/rand_query_%s_logs_by_%s_size_%s_readers_batch_%s_by_%s.yaml",
+			autoFileName = fmt.Sprintf("test-scripts/rand_query_%s_logs_by_%s_size_%s_readers_batch_%s_by_%s%s.yaml",
 				utils.HumanReadableSizePrecision(float64(concLogs), 0),
 				utils.HumanReadableBytesPrecision(float64(logSize), 0),
 				utils.HumanReadableSizePrecision(float64(readers), 0),
 				utils.HumanReadableSizePrecision(float64(queryStep), 0),
-				utils.HumanReadableBytesPrecision(float64(msg), 0))
+				utils.HumanReadableBytesPrecision(float64(msg), 0),
+				datasetFileSuffix(ds))
+		case "logs_meta":
+			logs, _ := strconv.Atoi(args[2])
+			creators, _ := strconv.Atoi(args[3])
+			tags, _ := strconv.Atoi(args[4])
+			cardinality, _ := strconv.Atoi(args[5])
+			readers, _ := strconv.Atoi(args[6])
+			queries, _ := strconv.Atoi(args[7])
+			cfg = server.BuildConfig(server.LogsMeta, &server.LogsMetaCfg{
+				Logs:        logs,
+				Creators:    creators,
+				TagsPerLog:  tags,
+				Cardinality: cardinality,
+				Readers:     readers,
+				Queries:     queries,
+			})
+			autoFileName = fmt.Sprintf("test-scripts/logs_meta_%s_logs_%s_creators_%s_tags_by_%s_values_%s_readers_by_%s_queries.yaml",
+				utils.HumanReadableSizePrecision(float64(logs), 0),
+				utils.HumanReadableSizePrecision(float64(creators), 0),
+				utils.HumanReadableSizePrecision(float64(tags), 0),
+				utils.HumanReadableSizePrecision(float64(cardinality), 0),
+				utils.HumanReadableSizePrecision(float64(readers), 0),
+				utils.HumanReadableSizePrecision(float64(queries), 0))
 		}
 
 		jsCfg, err := configs.ToJson(cfg)
@@ -123,3 +176,35 @@ var generateCfgCmd = &cobra.Command{
 		return nil
 	},
 }
+
+// datasetArg parses the optional keep=<dataset> (or reuse=<dataset> if allowed) argument
+func datasetArg(args []string, reuseAllowed bool) (server.Dataset, error) {
+	var ds server.Dataset
+	if len(args) == 0 {
+		return ds, nil
+	}
+	mode, name, _ := strings.Cut(args[0], "=")
+	if len(name) == 0 {
+		return ds, fmt.Errorf("dataset name is expected in %q", args[0])
+	}
+	ds.Name = name
+	switch {
+	case mode == "keep":
+		ds.Keep = true
+	case mode == "reuse" && reuseAllowed:
+		ds.Reuse = true
+	default:
+		return ds, fmt.Errorf("unexpected dataset argument %q", args[0])
+	}
+	return ds, nil
+}
+
+func datasetFileSuffix(ds server.Dataset) string {
+	switch {
+	case ds.Keep:
+		return "_keep_" + ds.Name
+	case ds.Reuse:
+		return "_reuse_" + ds.Name
+	}
+	return ""
+}
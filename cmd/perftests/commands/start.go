@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"fmt"
 	"os"
 	"strings"
 	"syscall"
@@ -12,11 +13,31 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	coordinator bool
+	worker      bool
+	coordCfg    server.CoordinationCfg
+)
+
 var startCmd = &cobra.Command{
 	Use:   "start config.yaml",
 	Short: "Starts the service: perftests start {cfg_file_names}...}",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Starts the service: perftests start {cfg_file_names}...}
+
+With --coordinator the tests of the config files are published as the plan of
+the run identified by --run-id, the coordinator awaits the reports of the
+--workers and writes the aggregated report to the summary path. Workers are
+started with --worker and the run ID only, they pull the plan, run it and
+report the results back to the coordinator. The run is coordinated through
+the Solaris --address.`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(c *cobra.Command, args []string) error {
+		if coordinator && worker {
+			return fmt.Errorf("--coordinator and --worker are mutually exclusive")
+		}
+		if !worker && len(args) == 0 {
+			return fmt.Errorf("at least one config file is expected")
+		}
 		envVarsCfg, err := configs.LoadFromEnvVars()
 		if err != nil {
 			return err
@@ -36,6 +57,25 @@ var startCmd = &cobra.Command{
 			}
 		}
 		mainCtx := context.NewSignalsContext(os.Interrupt, syscall.SIGTERM)
+		switch {
+		case coordinator:
+			return server.RunCoordinator(mainCtx, appCfg, coordCfg)
+		case worker:
+			return server.RunWorker(mainCtx, appCfg, coordCfg)
+		}
 		return server.Run(mainCtx, appCfg)
 	},
 }
+
+func init() {
+	address := os.Getenv("PERFTESTS_SOLARIS_ADDRESS")
+	if len(address) == 0 {
+		address = "localhost:50051"
+	}
+	startCmd.Flags().BoolVar(&coordinator, "coordinator", false, "publish the tests to the workers of the run and aggregate their reports")
+	startCmd.Flags().BoolVar(&worker, "worker", false, "run the tests published by the coordinator of the run")
+	startCmd.Flags().StringVar(&coordCfg.RunID, "run-id", os.Getenv("PERFTESTS_RUN_ID"), "the run ID the coordinator and the workers join")
+	startCmd.Flags().StringVar(&coordCfg.Address, "address", address, "the Solaris address the run is coordinated through")
+	startCmd.Flags().IntVar(&coordCfg.Workers, "workers", 1, "the number of workers the coordinator awaits")
+	startCmd.Flags().DurationVar(&coordCfg.Timeout, "timeout", 0, "the limit of awaiting the plan, 10m if 0, or the reports, no limit if 0")
+}
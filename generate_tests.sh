@@ -116,4 +116,10 @@
 #------------------
 # generate seq query from 10 logs 2GB size by 10 readers, batch 500 by 100KB
 ./build/perftests generateCfg auto rand_query 10 2143741824 10 500 102400
-#------------------
\ No newline at end of file
+#------------------
+# generate logs metadata: 100K logs by 10 creators, 10 tags by 100 values, 10 readers by 100 queries
+./build/perftests generateCfg auto logs_meta 100000 10 10 100 10 100
+#------------------
+# generate logs metadata: 1M logs by 100 creators, 20 tags by 1000 values, 10 readers by 100 queries
+./build/perftests generateCfg auto logs_meta 1000000 100 20 1000 10 100
+#------------------
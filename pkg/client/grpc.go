@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/inmem"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+)
+
+type (
+	// GrpcCfg describes the gRPC client connection, the zero value means
+	// the insecure connection (TLS for the port 443) with the default options
+	GrpcCfg struct {
+		TLS *TLSCfg `yaml:"tls,omitempty" json:"tls,omitempty"`
+		// Metadata contains the headers added to every call
+		Metadata map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+		// EnvMetadata contains the headers added to every call with the values
+		// read from the env variables, e.g. {"authorization": "AUTH_HEADER"}
+		EnvMetadata map[string]string `yaml:"envMetadata,omitempty" json:"envMetadata,omitempty"`
+		// Compression is the compressor of the requests, "gzip" or none if empty
+		Compression string        `yaml:"compression,omitempty" json:"compression,omitempty"`
+		Keepalive   *KeepaliveCfg `yaml:"keepalive,omitempty" json:"keepalive,omitempty"`
+		// MaxMsgSize is the max size of the sent and received messages in bytes
+		MaxMsgSize int `yaml:"maxMsgSize,omitempty" json:"maxMsgSize,omitempty"`
+		// DialTimeout is how long the dial waits for the connection to be
+		// ready, the dial does not wait for the connection if empty
+		DialTimeout string `yaml:"dialTimeout,omitempty" json:"dialTimeout,omitempty"`
+	}
+
+	// TLSCfg describes the transport security of the connection
+	TLSCfg struct {
+		// Mode is one of the TLS modes, TLSAuto by default
+		Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+		// CAFile is the PEM file of the CA certificates (TLSCustomCA and TLSMutual modes),
+		// the system CA certificates are used if empty
+		CAFile string `yaml:"caFile,omitempty" json:"caFile,omitempty"`
+		// CertFile and KeyFile are the PEM files of the client certificate (TLSMutual mode)
+		CertFile string `yaml:"certFile,omitempty" json:"certFile,omitempty"`
+		KeyFile  string `yaml:"keyFile,omitempty" json:"keyFile,omitempty"`
+		// ServerName overrides the server name the certificate is verified for
+		ServerName string `yaml:"serverName,omitempty" json:"serverName,omitempty"`
+	}
+
+	// KeepaliveCfg describes the client keepalive pings
+	KeepaliveCfg struct {
+		Time                string `yaml:"time,omitempty" json:"time,omitempty"`
+		Timeout             string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+		PermitWithoutStream bool   `yaml:"permitWithoutStream,omitempty" json:"permitWithoutStream,omitempty"`
+	}
+)
+
+const (
+	// TLSAuto means TLSSystem for the port 443 and TLSOff otherwise
+	TLSAuto = "auto"
+	// TLSOff means the insecure connection
+	TLSOff = "off"
+	// TLSSystem means TLS with the system CA certificates
+	TLSSystem = "system"
+	// TLSCustomCA means TLS with the CA certificates of the CAFile
+	TLSCustomCA = "ca"
+	// TLSMutual means TLS with the client certificate
+	TLSMutual = "mtls"
+
+	// DefaultMaxMsgSize is the default max size of the sent and received messages
+	DefaultMaxMsgSize = 100 * 1024 * 1024 //100MB
+)
+
+// Address returns the value of the env variable if it is set, otherwise
+// the address is returned
+func Address(address, envVar string) string {
+	if len(envVar) > 0 {
+		if res, ok := os.LookupEnv(envVar); ok {
+			return res
+		}
+	}
+	return address
+}
+
+// Dial returns the connection to the address described by the config,
+// the nil config means the defaults. The in-memory server addresses
+// (see inmem.Scheme) are connected in-process.
+func Dial(ctx context.Context, addr string, cfg *GrpcCfg) (*grpc.ClientConn, error) {
+	if cfg == nil {
+		cfg = &GrpcCfg{}
+	}
+	creds, err := transportCredentials(addr, cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	maxMsgSize := cfg.MaxMsgSize
+	if maxMsgSize <= 0 {
+		maxMsgSize = DefaultMaxMsgSize
+	}
+	callOpts := []grpc.CallOption{grpc.MaxCallRecvMsgSize(maxMsgSize), grpc.MaxCallSendMsgSize(maxMsgSize)}
+	switch cfg.Compression {
+	case "":
+	case gzip.Name:
+		callOpts = append(callOpts, grpc.UseCompressor(gzip.Name))
+	default:
+		return nil, fmt.Errorf("unknown compression %q: %w", cfg.Compression, errors.ErrInvalid)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
+		grpc.WithDefaultCallOptions(callOpts...),
+	}
+	if inmem.IsAddress(addr) {
+		opts = append(opts, grpc.WithContextDialer(inmem.Serve(addr).Dial))
+		addr = "passthrough:///" + addr
+	}
+
+	md, err := buildMetadata(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if md.Len() > 0 {
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				return invoker(withMetadata(ctx, md), method, req, reply, cc, opts...)
+			}),
+			grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return streamer(withMetadata(ctx, md), desc, cc, method, opts...)
+			}))
+	}
+
+	if cfg.Keepalive != nil {
+		params := keepalive.ClientParameters{PermitWithoutStream: cfg.Keepalive.PermitWithoutStream}
+		if params.Time, err = parseDuration(cfg.Keepalive.Time); err != nil {
+			return nil, fmt.Errorf("failed to parse keepalive time: %w", err)
+		}
+		if params.Timeout, err = parseDuration(cfg.Keepalive.Timeout); err != nil {
+			return nil, fmt.Errorf("failed to parse keepalive timeout: %w", err)
+		}
+		opts = append(opts, grpc.WithKeepaliveParams(params))
+	}
+
+	dialTimeout, err := parseDuration(cfg.DialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dial timeout: %w", err)
+	}
+	if dialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dialTimeout)
+		defer cancel()
+		opts = append(opts, grpc.WithBlock(), grpc.WithReturnConnectionError())
+	}
+	return grpc.DialContext(ctx, addr, opts...)
+}
+
+func transportCredentials(addr string, cfg *TLSCfg) (credentials.TransportCredentials, error) {
+	if cfg == nil {
+		cfg = &TLSCfg{}
+	}
+	mode := cfg.Mode
+	if len(mode) == 0 || mode == TLSAuto {
+		mode = TLSOff
+		if isTLSPort(addr) {
+			mode = TLSSystem
+		}
+	}
+	tlsCfg := &tls.Config{ServerName: cfg.ServerName}
+	switch mode {
+	case TLSOff:
+		return insecure.NewCredentials(), nil
+	case TLSSystem:
+	case TLSCustomCA, TLSMutual:
+		if len(cfg.CAFile) > 0 {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file: %w", err)
+			}
+			tlsCfg.RootCAs = x509.NewCertPool()
+			if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no CA certificates found in %s: %w", cfg.CAFile, errors.ErrInvalid)
+			}
+		} else if mode == TLSCustomCA {
+			return nil, fmt.Errorf("CA file must be set in %s mode: %w", mode, errors.ErrInvalid)
+		}
+		if mode == TLSMutual {
+			cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			tlsCfg.Certificates = []tls.Certificate{cert}
+		}
+	default:
+		return nil, fmt.Errorf("unknown TLS mode %q: %w", cfg.Mode, errors.ErrInvalid)
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+func buildMetadata(cfg *GrpcCfg) (metadata.MD, error) {
+	md := metadata.MD{}
+	for k, v := range cfg.Metadata {
+		md.Append(k, v)
+	}
+	for k, envVar := range cfg.EnvMetadata {
+		v, ok := os.LookupEnv(envVar)
+		if !ok {
+			return nil, fmt.Errorf("env variable %s of the header %s is not set: %w", envVar, k, errors.ErrNotExist)
+		}
+		md.Append(k, v)
+	}
+	return md, nil
+}
+
+func withMetadata(ctx context.Context, md metadata.MD) context.Context {
+	if out, ok := metadata.FromOutgoingContext(ctx); ok {
+		return metadata.NewOutgoingContext(ctx, metadata.Join(out, md))
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func isTLSPort(addr string) bool {
+	idx := strings.LastIndex(addr, ":")
+	if idx == -1 {
+		return false // insecure
+	}
+	return strings.TrimSpace(addr[idx+1:]) == "443"
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAddress(t *testing.T) {
+	t.Setenv("PERFTESTS_TEST_ADDRESS", "host:1234")
+	assert.Equal(t, "host:1234", Address("localhost:50051", "PERFTESTS_TEST_ADDRESS"))
+	assert.Equal(t, "localhost:50051", Address("localhost:50051", "PERFTESTS_TEST_ABSENT"))
+	assert.Equal(t, "localhost:50051", Address("localhost:50051", ""))
+}
+
+func TestTransportCredentials(t *testing.T) {
+	creds, err := transportCredentials("localhost:50051", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, insecure.NewCredentials().Info().SecurityProtocol, creds.Info().SecurityProtocol)
+	creds, err = transportCredentials("solaris.example.com:443", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "tls", creds.Info().SecurityProtocol)
+	creds, err = transportCredentials("localhost:50051", &TLSCfg{Mode: TLSSystem, ServerName: "solaris"})
+	assert.NoError(t, err)
+	assert.Equal(t, "solaris", creds.Info().ServerName)
+
+	_, err = transportCredentials("localhost:50051", &TLSCfg{Mode: TLSCustomCA})
+	assert.Error(t, err)
+	_, err = transportCredentials("localhost:50051", &TLSCfg{Mode: TLSCustomCA, CAFile: filepath.Join(t.TempDir(), "absent.pem")})
+	assert.Error(t, err)
+	invalidCA := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(invalidCA, []byte("not a certificate"), 0644))
+	_, err = transportCredentials("localhost:50051", &TLSCfg{Mode: TLSCustomCA, CAFile: invalidCA})
+	assert.Error(t, err)
+	_, err = transportCredentials("localhost:50051", &TLSCfg{Mode: TLSMutual})
+	assert.Error(t, err)
+	_, err = transportCredentials("localhost:50051", &TLSCfg{Mode: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestDial(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	mdCh := make(chan metadata.MD, 1)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		mdCh <- md
+		return handler(ctx, req)
+	}))
+	grpc_health_v1.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	t.Setenv("PERFTESTS_TEST_TOKEN", "Bearer token")
+	conn, err := Dial(context.Background(), lis.Addr().String(), &GrpcCfg{
+		Metadata:    map[string]string{"x-tenant": "perftests"},
+		EnvMetadata: map[string]string{"authorization": "PERFTESTS_TEST_TOKEN"},
+		Compression: "gzip",
+		Keepalive:   &KeepaliveCfg{Time: "30s", Timeout: "5s"},
+		DialTimeout: "5s",
+	})
+	assert.NoError(t, err)
+	defer conn.Close()
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	md := <-mdCh
+	assert.Equal(t, []string{"perftests"}, md.Get("x-tenant"))
+	assert.Equal(t, []string{"Bearer token"}, md.Get("authorization"))
+
+	_, err = Dial(context.Background(), lis.Addr().String(), &GrpcCfg{EnvMetadata: map[string]string{"authorization": "PERFTESTS_TEST_ABSENT"}})
+	assert.Error(t, err)
+	_, err = Dial(context.Background(), lis.Addr().String(), &GrpcCfg{Compression: "zstd"})
+	assert.Error(t, err)
+
+	// the blocking dial fails if the server is not available
+	srv.Stop()
+	_, err = Dial(context.Background(), lis.Addr().String(), &GrpcCfg{DialTimeout: "100ms"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+type (
+	// Pool is the grpc.ClientConnInterface spreading the calls over several
+	// connections, so the concurrent calls are not multiplexed over one
+	// HTTP/2 connection. Pool is safe for concurrent use.
+	Pool struct {
+		conns       []*grpc.ClientConn
+		next        atomic.Uint64
+		affinityKey func(ctx context.Context) string
+		onCall      func(idx int)
+	}
+)
+
+const (
+	// BalancingRoundRobin means the calls go to the connections one by one
+	BalancingRoundRobin = "roundRobin"
+	// BalancingAffinity means the calls of the same affinity key go to the
+	// same connection, the calls with no key are balanced round-robin
+	BalancingAffinity = "affinity"
+)
+
+// NewPool returns the pool of the connections. The affinityKey returns the
+// key of the call context, the calls are balanced round-robin if it is nil.
+// The onCall is called with the index of the connection chosen for every
+// call, it may be nil.
+func NewPool(conns []*grpc.ClientConn, affinityKey func(ctx context.Context) string, onCall func(idx int)) *Pool {
+	return &Pool{conns: conns, affinityKey: affinityKey, onCall: onCall}
+}
+
+// Addresses returns the comma-separated addresses of the env variable if it
+// is set, otherwise the non-empty addresses are returned
+func Addresses(addresses []string, envVar string) []string {
+	var res []string
+	if len(envVar) > 0 {
+		if env, ok := os.LookupEnv(envVar); ok {
+			addresses = strings.Split(env, ",")
+		}
+	}
+	for _, addr := range addresses {
+		if addr = strings.TrimSpace(addr); len(addr) > 0 {
+			res = append(res, addr)
+		}
+	}
+	return res
+}
+
+// Invoke performs the unary call on one of the pool connections
+func (p *Pool) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	return p.conns[p.pick(ctx)].Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream begins the streaming call on one of the pool connections
+func (p *Pool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return p.conns[p.pick(ctx)].NewStream(ctx, desc, method, opts...)
+}
+
+// Size returns the number of the pool connections
+func (p *Pool) Size() int {
+	return len(p.conns)
+}
+
+// Close closes all the pool connections
+func (p *Pool) Close() error {
+	var errs []error
+	for _, conn := range p.conns {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Pool) pick(ctx context.Context) int {
+	var idx int
+	var key string
+	if p.affinityKey != nil {
+		key = p.affinityKey(ctx)
+	}
+	if len(key) > 0 {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+		idx = int(h.Sum32() % uint32(len(p.conns)))
+	} else {
+		idx = int((p.next.Add(1) - 1) % uint64(len(p.conns)))
+	}
+	if p.onCall != nil {
+		p.onCall(idx)
+	}
+	return idx
+}
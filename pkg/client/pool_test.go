@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type affinityKey struct{}
+
+func TestAddresses(t *testing.T) {
+	t.Setenv("PERFTESTS_TEST_ADDRESSES", "host1:1234, host2:1234,")
+	assert.Equal(t, []string{"host1:1234", "host2:1234"}, Addresses([]string{"localhost:50051"}, "PERFTESTS_TEST_ADDRESSES"))
+	assert.Equal(t, []string{"localhost:50051", "localhost:50052"}, Addresses([]string{"localhost:50051", "", "localhost:50052"}, "PERFTESTS_TEST_ABSENT"))
+	assert.Nil(t, Addresses([]string{""}, ""))
+}
+
+func TestPool(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	srv := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	var conns []*grpc.ClientConn
+	for i := 0; i < 3; i++ {
+		conn, err := Dial(context.Background(), lis.Addr().String(), nil)
+		assert.NoError(t, err)
+		conns = append(conns, conn)
+	}
+	calls := make([]int, len(conns))
+	pool := NewPool(conns, func(ctx context.Context) string {
+		key, _ := ctx.Value(affinityKey{}).(string)
+		return key
+	}, func(idx int) { calls[idx]++ })
+	defer pool.Close()
+	assert.Equal(t, 3, pool.Size())
+
+	hc := grpc_health_v1.NewHealthClient(pool)
+	for i := 0; i < 6; i++ {
+		_, err = hc.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []int{2, 2, 2}, calls)
+
+	ctx := context.WithValue(context.Background(), affinityKey{}, "log1")
+	for i := 0; i < 6; i++ {
+		_, err = hc.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		assert.NoError(t, err)
+	}
+	var pinned int
+	for _, n := range calls {
+		if n == 8 {
+			pinned++
+		}
+	}
+	assert.Equal(t, 1, pinned)
+}
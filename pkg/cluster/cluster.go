@@ -4,14 +4,29 @@ import "context"
 
 type (
 	Cluster interface {
+		ID() string
 		AddNode(ctx context.Context) (Node, error)
 		Nodes(ctx context.Context) ([]Node, error)
+		// Generation returns the nodes of the run the node joined, which
+		// are the nodes added after the last node finished before the node
+		// is added, so the nodes of the former runs are not counted
+		Generation(ctx context.Context, nodeID string) ([]Node, error)
+		// Arrived returns the IDs of the nodes reached the barrier
+		Arrived(ctx context.Context, barrier string) ([]string, error)
+		// PublishPlan publishes the test plan the cluster nodes run
+		PublishPlan(ctx context.Context, plan []byte) error
+		// Plan returns the last published test plan, it awaits the plan
+		// until it is published or the context is closed
+		Plan(ctx context.Context) ([]byte, error)
 		Delete(ctx context.Context) error
 	}
 
 	Node interface {
+		ID() string
 		Finish(ctx context.Context, result []byte) error
 		Result(ctx context.Context) ([]byte, error)
+		// Arrive records the node reached the barrier
+		Arrive(ctx context.Context, barrier string) error
 		Delete(ctx context.Context) error
 	}
 )
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/solarisdb/perftests/pkg/cluster"
 	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
 	"github.com/solarisdb/solaris/golibs/logging"
 	"github.com/solarisdb/solaris/golibs/ulidutils"
 )
@@ -24,11 +26,18 @@ type (
 		nodeID    string
 		nodeLogID string
 		cluster   *solarCluster
+		finished  atomic.Bool
 	}
 
+	// clusterRecord is the record of the cluster log, it is either the
+	// node added to the cluster, the node reached the Barrier, the node
+	// Finished or the published test Plan
 	clusterRecord struct {
-		NodeID    string `json:"node_id"`
-		NodeLogID string `json:"node_log_id"`
+		NodeID    string `json:"node_id,omitempty"`
+		NodeLogID string `json:"node_log_id,omitempty"`
+		Barrier   string `json:"barrier,omitempty"`
+		Finished  bool   `json:"finished,omitempty"`
+		Plan      []byte `json:"plan,omitempty"`
 	}
 
 	nodeResult struct {
@@ -36,7 +45,11 @@ type (
 	}
 )
 
-const prefix = "solarisdb.perftests.cluster"
+const (
+	prefix = "solarisdb.perftests.cluster"
+	// resultPollPeriod is how often the node result or the plan is checked while awaiting it
+	resultPollPeriod = 5 * time.Second
+)
 
 var _ cluster.Cluster = (*solarCluster)(nil)
 var _ cluster.Node = (*solarisNode)(nil)
@@ -51,6 +64,10 @@ func NewCluster(ctx context.Context, clusterID string, solaris solaris.ServiceCl
 	return sc, err
 }
 
+func (s *solarCluster) ID() string {
+	return s.clusterID
+}
+
 func (s *solarCluster) AddNode(ctx context.Context) (cluster.Node, error) {
 	nodeID := ulidutils.NewUUID().String()
 	node, err := newNode(ctx, nodeID, s)
@@ -65,6 +82,99 @@ func (s *solarCluster) AddNode(ctx context.Context) (cluster.Node, error) {
 
 func (s *solarCluster) Nodes(ctx context.Context) ([]cluster.Node, error) {
 	var nodes []cluster.Node
+	err := s.forEachRecord(ctx, func(rec clusterRecord) {
+		if len(rec.NodeLogID) > 0 {
+			nodes = append(nodes, &solarisNode{
+				cluster:   s,
+				nodeID:    rec.NodeID,
+				nodeLogID: rec.NodeLogID,
+			})
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nodes: %w", err)
+	}
+	return nodes, nil
+}
+
+func (s *solarCluster) Generation(ctx context.Context, nodeID string) ([]cluster.Node, error) {
+	var nodes []cluster.Node
+	var found, closed bool
+	err := s.forEachRecord(ctx, func(rec clusterRecord) {
+		switch {
+		case closed:
+		case rec.Finished:
+			// the node finished before the node is added ends the former
+			// run, the one finished after it ends the run of the node
+			if found {
+				closed = true
+			} else {
+				nodes = nil
+			}
+		case len(rec.NodeLogID) > 0:
+			nodes = append(nodes, &solarisNode{
+				cluster:   s,
+				nodeID:    rec.NodeID,
+				nodeLogID: rec.NodeLogID,
+			})
+			found = found || rec.NodeID == nodeID
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nodes: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("node %s is not added to the cluster: %w", nodeID, errors.ErrNotExist)
+	}
+	return nodes, nil
+}
+
+func (s *solarCluster) Arrived(ctx context.Context, barrier string) ([]string, error) {
+	var nodeIDs []string
+	seen := map[string]bool{}
+	err := s.forEachRecord(ctx, func(rec clusterRecord) {
+		if rec.Barrier == barrier && !seen[rec.NodeID] {
+			seen[rec.NodeID] = true
+			nodeIDs = append(nodeIDs, rec.NodeID)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query barrier %s: %w", barrier, err)
+	}
+	return nodeIDs, nil
+}
+
+func (s *solarCluster) PublishPlan(ctx context.Context, plan []byte) error {
+	if err := s.addRecord(ctx, clusterRecord{Plan: plan}); err != nil {
+		return fmt.Errorf("failed to publish plan: %w", err)
+	}
+	return nil
+}
+
+func (s *solarCluster) Plan(ctx context.Context) ([]byte, error) {
+	for {
+		var plan []byte
+		err := s.forEachRecord(ctx, func(rec clusterRecord) {
+			if len(rec.Plan) > 0 {
+				plan = rec.Plan
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query plan: %w", err)
+		}
+		if len(plan) > 0 {
+			return plan, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("plan is not published: %w", ctx.Err())
+		case <-time.After(resultPollPeriod):
+		}
+	}
+}
+
+// forEachRecord calls f for every record of the cluster log
+func (s *solarCluster) forEachRecord(ctx context.Context, f func(rec clusterRecord)) error {
 	fromID := ""
 	for {
 		req := &solaris.QueryRecordsRequest{
@@ -74,23 +184,19 @@ func (s *solarCluster) Nodes(ctx context.Context) ([]cluster.Node, error) {
 		}
 		res, err := s.solaris.QueryRecords(ctx, req)
 		if err != nil {
-			return nil, fmt.Errorf("failed to query nodes: %w", err)
+			return err
 		}
-		for _, nodeRec := range res.Records {
-			var node clusterRecord
-			_ = json.Unmarshal(nodeRec.Payload, &node)
-			nodes = append(nodes, &solarisNode{
-				cluster:   s,
-				nodeID:    node.NodeID,
-				nodeLogID: node.NodeLogID,
-			})
+		for _, rec := range res.Records {
+			var cr clusterRecord
+			_ = json.Unmarshal(rec.Payload, &cr)
+			f(cr)
 		}
 		fromID = res.NextPageID
 		if fromID == "" {
 			break
 		}
 	}
-	return nodes, nil
+	return nil
 }
 
 func (s *solarCluster) getOrCreateLog(ctx context.Context) (string, error) {
@@ -119,17 +225,21 @@ func (s *solarCluster) getOrCreateLog(ctx context.Context) (string, error) {
 }
 
 func (s *solarCluster) addNode(ctx context.Context, clusterLogID string, node *solarisNode) error {
-	nodeRec, _ := json.Marshal(clusterRecord{NodeID: node.nodeID, NodeLogID: node.nodeLogID})
+	if err := s.addRecord(ctx, clusterRecord{NodeID: node.nodeID, NodeLogID: node.nodeLogID}); err != nil {
+		return fmt.Errorf("failed to append node to cluster log %s: %w", clusterLogID, err)
+	}
+	return nil
+}
+
+func (s *solarCluster) addRecord(ctx context.Context, rec clusterRecord) error {
+	payload, _ := json.Marshal(rec)
 	_, err := s.solaris.AppendRecords(ctx, &solaris.AppendRecordsRequest{
-		LogID: clusterLogID,
+		LogID: s.clusterLogID,
 		Records: []*solaris.Record{
-			{Payload: nodeRec},
+			{Payload: payload},
 		},
 	})
-	if err != nil {
-		return fmt.Errorf("failed to append node to cluster log %s: %w", clusterLogID, err)
-	}
-	return nil
+	return err
 }
 
 func (s *solarCluster) Delete(ctx context.Context) error {
@@ -159,7 +269,14 @@ func newNode(ctx context.Context, nodeID string, cluster *solarCluster) (*solari
 	return sc, err
 }
 
+func (s *solarisNode) ID() string {
+	return s.nodeID
+}
+
 func (s *solarisNode) Finish(ctx context.Context, result []byte) error {
+	if !s.finished.CompareAndSwap(false, true) {
+		return nil
+	}
 	//nodeRec, _ := json.Marshal(nodeResult{Result: string(result)})
 	_, err := s.cluster.solaris.AppendRecords(ctx, &solaris.AppendRecordsRequest{
 		LogID: s.nodeLogID,
@@ -167,7 +284,21 @@ func (s *solarisNode) Finish(ctx context.Context, result []byte) error {
 			{Payload: result},
 		},
 	})
-	return err
+	if err != nil {
+		s.finished.Store(false)
+		return err
+	}
+	if err = s.cluster.addRecord(ctx, clusterRecord{NodeID: s.nodeID, Finished: true}); err != nil {
+		return fmt.Errorf("failed to append node finish to cluster log: %w", err)
+	}
+	return nil
+}
+
+func (s *solarisNode) Arrive(ctx context.Context, barrier string) error {
+	if err := s.cluster.addRecord(ctx, clusterRecord{NodeID: s.nodeID, Barrier: barrier}); err != nil {
+		return fmt.Errorf("failed to append node to barrier %s: %w", barrier, err)
+	}
+	return nil
 }
 
 func (s *solarisNode) Delete(ctx context.Context) error {
@@ -191,7 +322,11 @@ func (s *solarisNode) Result(ctx context.Context) ([]byte, error) {
 			return nil, fmt.Errorf("failed to query node result: %w", err)
 		}
 		if len(res.Records) == 0 {
-			time.Sleep(5 * time.Second)
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("node result is not ready: %w", ctx.Err())
+			case <-time.After(resultPollPeriod):
+			}
 			continue
 		}
 		rec := res.Records[0]
@@ -0,0 +1,384 @@
+package inmem
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/utils"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/ulidutils"
+	"github.com/solarisdb/solaris/pkg/ql"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type (
+	// Server is the in-memory implementation of the solaris.ServiceServer
+	// for the hermetic tests and the local dry runs of the scenarios. The
+	// server is served over the in-process connection and is addressed as
+	// "inmem://<name>", the servers of the same name are the same server.
+	// The data is not persisted and lives as long as the process.
+	Server struct {
+		solaris.UnimplementedServiceServer
+
+		lis    *bufconn.Listener
+		gs     *grpc.Server
+		faults atomic.Pointer[faults]
+
+		lock sync.RWMutex
+		logs map[string]*logEntry
+	}
+
+	// Config describes the faults injected into every call of the server,
+	// the zero value means no faults
+	Config struct {
+		// Latency is the latency distribution function in milliseconds
+		// (see utils.ParseDistribution), e.g. "normal(5, 2)"
+		Latency string `yaml:"latency,omitempty" json:"latency,omitempty"`
+		// ErrorRate is the share of the calls failed with the ErrorCode, from 0 to 1
+		ErrorRate float64 `yaml:"errorRate,omitempty" json:"errorRate,omitempty"`
+		// ErrorCode is the gRPC status code of the injected errors, UNAVAILABLE by default
+		ErrorCode string `yaml:"errorCode,omitempty" json:"errorCode,omitempty"`
+	}
+
+	faults struct {
+		latency   utils.Distribution
+		errorRate float64
+		errorCode codes.Code
+	}
+
+	logEntry struct {
+		log     *solaris.Log
+		records []*solaris.Record // sorted by ID
+	}
+)
+
+const (
+	// Scheme is the scheme of the in-memory server addresses
+	Scheme = "inmem://"
+
+	bufSize          = 1024 * 1024
+	defaultLogsLimit = 50
+	maxLogsLimit     = 1000
+	maxRecordsLimit  = 10000
+)
+
+var (
+	serversLock sync.Mutex
+	servers     = map[string]*Server{}
+)
+
+// IsAddress returns true if the address is the in-memory server address
+func IsAddress(addr string) bool {
+	return strings.HasPrefix(addr, Scheme)
+}
+
+// Serve returns the server of the address, the server is created and
+// started on the first call for the address
+func Serve(addr string) *Server {
+	name := strings.TrimPrefix(addr, Scheme)
+	serversLock.Lock()
+	defer serversLock.Unlock()
+	if s, ok := servers[name]; ok {
+		return s
+	}
+	s := NewServer()
+	s.lis = bufconn.Listen(bufSize)
+	s.gs = grpc.NewServer(grpc.ChainUnaryInterceptor(s.injectFaults))
+	solaris.RegisterServiceServer(s.gs, s)
+	go func() {
+		_ = s.gs.Serve(s.lis)
+	}()
+	servers[name] = s
+	return s
+}
+
+// NewServer returns the new empty server, the server is not served, see Serve
+func NewServer() *Server {
+	return &Server{logs: map[string]*logEntry{}}
+}
+
+// Dial returns the new in-process connection to the server,
+// it may be used as the grpc.WithContextDialer function
+func (s *Server) Dial(ctx context.Context, _ string) (net.Conn, error) {
+	return s.lis.DialContext(ctx)
+}
+
+// SetConfig sets the faults injected into the calls, the nil config means no faults
+func (s *Server) SetConfig(cfg *Config) error {
+	if cfg == nil {
+		s.faults.Store(nil)
+		return nil
+	}
+	f := &faults{errorRate: cfg.ErrorRate, errorCode: codes.Unavailable}
+	if len(cfg.Latency) > 0 {
+		var err error
+		if f.latency, err = utils.ParseDistribution(cfg.Latency); err != nil {
+			return fmt.Errorf("failed to parse latency: %w", err)
+		}
+	}
+	if len(cfg.ErrorCode) > 0 {
+		if err := f.errorCode.UnmarshalJSON([]byte(strconv.Quote(cfg.ErrorCode))); err != nil {
+			return fmt.Errorf("unknown error code %q: %w", cfg.ErrorCode, errors.ErrInvalid)
+		}
+	}
+	s.faults.Store(f)
+	return nil
+}
+
+// Reset removes all the logs and records of the server
+func (s *Server) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.logs = map[string]*logEntry{}
+}
+
+func (s *Server) injectFaults(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	f := s.faults.Load()
+	if f == nil {
+		return handler(ctx, req)
+	}
+	if f.latency != nil {
+		if latency := f.latency(); latency > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, status.FromContextError(ctx.Err()).Err()
+			case <-time.After(time.Duration(latency) * time.Millisecond):
+			}
+		}
+	}
+	if f.errorRate > 0 && rand.Float64() < f.errorRate {
+		return nil, status.Error(f.errorCode, "injected error")
+	}
+	return handler(ctx, req)
+}
+
+// CreateLog implements solaris.ServiceServer
+func (s *Server) CreateLog(ctx context.Context, log *solaris.Log) (*solaris.Log, error) {
+	now := timestamppb.Now()
+	le := &logEntry{log: &solaris.Log{ID: ulidutils.NewID(), Tags: copyTags(log.Tags), CreatedAt: now, UpdatedAt: now}}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.logs[le.log.ID] = le
+	return copyLog(le.log), nil
+}
+
+// UpdateLog implements solaris.ServiceServer
+func (s *Server) UpdateLog(ctx context.Context, log *solaris.Log) (*solaris.Log, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	le, ok := s.logs[log.ID]
+	if !ok {
+		return nil, errors.GRPCWrap(fmt.Errorf("log %s not found: %w", log.ID, errors.ErrNotExist))
+	}
+	le.log.Tags = copyTags(log.Tags)
+	le.log.UpdatedAt = timestamppb.Now()
+	return copyLog(le.log), nil
+}
+
+// QueryLogs implements solaris.ServiceServer. The logs are ordered by ID,
+// the page ID is the ID of the first log of the page.
+func (s *Server) QueryLogs(ctx context.Context, request *solaris.QueryLogsRequest) (*solaris.QueryLogsResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	logs, err := s.findLogs(request.Condition)
+	if err != nil {
+		return nil, errors.GRPCWrap(err)
+	}
+	limit := int(request.Limit)
+	if limit <= 0 {
+		limit = defaultLogsLimit
+	}
+	limit = min(limit, maxLogsLimit)
+	res := &solaris.QueryLogsResult{Total: int64(len(logs))}
+	idx := sort.Search(len(logs), func(i int) bool { return logs[i].ID >= request.PageID })
+	for ; idx < len(logs) && len(res.Logs) < limit; idx++ {
+		res.Logs = append(res.Logs, copyLog(logs[idx]))
+	}
+	if idx < len(logs) {
+		res.NextPageID = logs[idx].ID
+	}
+	return res, nil
+}
+
+// DeleteLogs implements solaris.ServiceServer, no logs are deleted if the condition is empty
+func (s *Server) DeleteLogs(ctx context.Context, request *solaris.DeleteLogsRequest) (*solaris.DeleteLogsResult, error) {
+	res := &solaris.DeleteLogsResult{}
+	if len(request.Condition) == 0 {
+		return res, nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	logs, err := s.findLogs(request.Condition)
+	if err != nil {
+		return nil, errors.GRPCWrap(err)
+	}
+	for _, log := range logs {
+		delete(s.logs, log.ID)
+		res.DeletedIDs = append(res.DeletedIDs, log.ID)
+	}
+	return res, nil
+}
+
+// AppendRecords implements solaris.ServiceServer
+func (s *Server) AppendRecords(ctx context.Context, request *solaris.AppendRecordsRequest) (*solaris.AppendRecordsResult, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	le, ok := s.logs[request.LogID]
+	if !ok {
+		return nil, errors.GRPCWrap(fmt.Errorf("log %s not found: %w", request.LogID, errors.ErrNotExist))
+	}
+	now := timestamppb.Now()
+	res := &solaris.AppendRecordsResult{Added: int64(len(request.Records))}
+	for _, r := range request.Records {
+		id := ulidutils.NewID()
+		if n := len(le.records); n > 0 && id <= le.records[n-1].ID {
+			id = ulidutils.NextID(le.records[n-1].ID)
+		}
+		le.records = append(le.records, &solaris.Record{ID: id, LogID: le.log.ID, CreatedAt: now, Payload: r.Payload})
+		if request.ExpandIDs {
+			res.RecordIDs = append(res.RecordIDs, id)
+		}
+	}
+	le.log.UpdatedAt = now
+	return res, nil
+}
+
+// QueryRecords implements solaris.ServiceServer. The records of several logs
+// are merged by ID, the next page ID is the ID of the first record of the next page.
+func (s *Server) QueryRecords(ctx context.Context, request *solaris.QueryRecordsRequest) (*solaris.QueryRecordsResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	records, _, err := s.findRecords(request)
+	if err != nil {
+		return nil, errors.GRPCWrap(err)
+	}
+	limit := int(request.Limit)
+	if limit <= 0 || limit > maxRecordsLimit {
+		limit = maxRecordsLimit
+	}
+	res := &solaris.QueryRecordsResult{}
+	if len(records) > limit {
+		res.NextPageID = records[limit].ID
+		records = records[:limit]
+	}
+	res.Records = make([]*solaris.Record, len(records))
+	for i, r := range records {
+		res.Records[i] = &solaris.Record{ID: r.ID, LogID: r.LogID, CreatedAt: r.CreatedAt, Payload: r.Payload}
+	}
+	return res, nil
+}
+
+// CountRecords implements solaris.ServiceServer
+func (s *Server) CountRecords(ctx context.Context, request *solaris.QueryRecordsRequest) (*solaris.CountResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	records, total, err := s.findRecords(request)
+	if err != nil {
+		return nil, errors.GRPCWrap(err)
+	}
+	return &solaris.CountResult{Total: int64(total), Count: int64(len(records))}, nil
+}
+
+// findLogs returns the logs matching the condition sorted by ID
+func (s *Server) findLogs(cond string) ([]*solaris.Log, error) {
+	f, err := buildFilter(cond, ql.LogsCondValueDialect)
+	if err != nil {
+		return nil, err
+	}
+	var res []*solaris.Log
+	for _, le := range s.logs {
+		if f(le.log) {
+			res = append(res, le.log)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+// findRecords returns the sorted records matching the request and
+// the total number of the records of the requested logs
+func (s *Server) findRecords(request *solaris.QueryRecordsRequest) ([]*solaris.Record, int, error) {
+	logIDs := request.LogIDs
+	if len(logIDs) == 0 {
+		logs, err := s.findLogs(request.LogsCondition)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, log := range logs {
+			logIDs = append(logIDs, log.ID)
+		}
+	}
+	f, err := buildFilter(request.Condition, ql.RecordsCondValueDialect)
+	if err != nil {
+		return nil, 0, err
+	}
+	var res []*solaris.Record
+	var total int
+	for _, id := range logIDs {
+		le, ok := s.logs[id]
+		if !ok {
+			if len(request.LogIDs) > 0 {
+				return nil, 0, fmt.Errorf("log %s not found: %w", id, errors.ErrNotExist)
+			}
+			continue
+		}
+		total += len(le.records)
+		for _, r := range le.records {
+			if len(request.StartRecordID) > 0 {
+				if request.Descending && r.ID > request.StartRecordID || !request.Descending && r.ID < request.StartRecordID {
+					continue
+				}
+			}
+			if f(r) {
+				res = append(res, r)
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if request.Descending {
+			return res[i].ID > res[j].ID
+		}
+		return res[i].ID < res[j].ID
+	})
+	return res, total, nil
+}
+
+func buildFilter[T any](cond string, dialect ql.Dialect[T]) (ql.ExprF[T], error) {
+	if len(strings.TrimSpace(cond)) == 0 {
+		return func(T) bool { return true }, nil
+	}
+	expr, err := ql.Parse(cond)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse condition %q: %v: %w", cond, err, errors.ErrInvalid)
+	}
+	f, err := ql.BuildExprF(expr, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %v: %w", cond, err, errors.ErrInvalid)
+	}
+	return f, nil
+}
+
+func copyLog(log *solaris.Log) *solaris.Log {
+	return &solaris.Log{ID: log.ID, Tags: copyTags(log.Tags), CreatedAt: log.CreatedAt, UpdatedAt: log.UpdatedAt}
+}
+
+func copyTags(tags map[string]string) map[string]string {
+	res := make(map[string]string, len(tags))
+	for k, v := range tags {
+		res[k] = v
+	}
+	return res
+}
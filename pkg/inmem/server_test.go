@@ -0,0 +1,95 @@
+package inmem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func testClient(t *testing.T, addr string) solaris.ServiceClient {
+	conn, err := grpc.Dial("passthrough:///"+addr, grpc.WithContextDialer(Serve(addr).Dial),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return solaris.NewServiceClient(conn)
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	clnt := testClient(t, "inmem://test-server")
+	assert.True(t, Serve("inmem://test-server") == Serve("inmem://test-server"))
+
+	log1, err := clnt.CreateLog(ctx, &solaris.Log{Tags: map[string]string{"app": "a"}})
+	assert.NoError(t, err)
+	log2, err := clnt.CreateLog(ctx, &solaris.Log{Tags: map[string]string{"app": "b"}})
+	assert.NoError(t, err)
+
+	_, err = clnt.AppendRecords(ctx, &solaris.AppendRecordsRequest{LogID: "unknown", Records: []*solaris.Record{{}}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	for _, logID := range []string{log1.ID, log2.ID} {
+		ar, err := clnt.AppendRecords(ctx, &solaris.AppendRecordsRequest{LogID: logID, ExpandIDs: true,
+			Records: []*solaris.Record{{Payload: []byte("1")}, {Payload: []byte("2")}, {Payload: []byte("3")}}})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), ar.Added)
+		assert.Len(t, ar.RecordIDs, 3)
+	}
+
+	// paging over one log
+	qr, err := clnt.QueryRecords(ctx, &solaris.QueryRecordsRequest{LogIDs: []string{log1.ID}, Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, qr.Records, 2)
+	assert.Equal(t, "1", string(qr.Records[0].Payload))
+	qr, err = clnt.QueryRecords(ctx, &solaris.QueryRecordsRequest{LogIDs: []string{log1.ID}, Limit: 2, StartRecordID: qr.NextPageID})
+	assert.NoError(t, err)
+	assert.Len(t, qr.Records, 1)
+	assert.Equal(t, "3", string(qr.Records[0].Payload))
+	assert.Empty(t, qr.NextPageID)
+
+	// descending over the logs selected by the condition
+	qr, err = clnt.QueryRecords(ctx, &solaris.QueryRecordsRequest{LogsCondition: "tag('app') IN ['a', 'b']", Descending: true, Limit: 4})
+	assert.NoError(t, err)
+	assert.Len(t, qr.Records, 4)
+	assert.Equal(t, log2.ID, qr.Records[0].LogID)
+	assert.Equal(t, "3", string(qr.Records[0].Payload))
+	assert.NotEmpty(t, qr.NextPageID)
+
+	cr, err := clnt.CountRecords(ctx, &solaris.QueryRecordsRequest{LogIDs: []string{log1.ID, log2.ID}, StartRecordID: qr.Records[3].ID})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), cr.Total)
+	assert.Equal(t, int64(4), cr.Count)
+
+	lr, err := clnt.QueryLogs(ctx, &solaris.QueryLogsRequest{Condition: "tag('app') = 'b'"})
+	assert.NoError(t, err)
+	assert.Len(t, lr.Logs, 1)
+	assert.Equal(t, log2.ID, lr.Logs[0].ID)
+	_, err = clnt.QueryLogs(ctx, &solaris.QueryLogsRequest{Condition: "tag('app' = "})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	dr, err := clnt.DeleteLogs(ctx, &solaris.DeleteLogsRequest{Condition: "tag('app') = 'a'"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{log1.ID}, dr.DeletedIDs)
+	lr, err = clnt.QueryLogs(ctx, &solaris.QueryLogsRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), lr.Total)
+}
+
+func TestServerFaults(t *testing.T) {
+	ctx := context.Background()
+	clnt := testClient(t, "inmem://test-faults")
+	s := Serve("inmem://test-faults")
+
+	assert.Error(t, s.SetConfig(&Config{ErrorCode: "NO_SUCH_CODE"}))
+	assert.NoError(t, s.SetConfig(&Config{ErrorRate: 1, ErrorCode: "RESOURCE_EXHAUSTED", Latency: "constant(1)"}))
+	_, err := clnt.CreateLog(ctx, &solaris.Log{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	assert.NoError(t, s.SetConfig(nil))
+	_, err = clnt.CreateLog(ctx, &solaris.Log{})
+	assert.NoError(t, err)
+}
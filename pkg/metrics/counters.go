@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	// Counters is a set of the counters identified by the keys, e.g. the
+	// failed calls counted by their gRPC status codes
+	Counters struct {
+		lock   sync.Mutex
+		counts map[string]int64
+	}
+
+	CountersMetricResult struct {
+		Total  int64            `yaml:"total" json:"total"`
+		Counts map[string]int64 `yaml:"counts" json:"counts"`
+	}
+)
+
+func NewCounters() *Counters {
+	return &Counters{counts: map[string]int64{}}
+}
+
+func (c *Counters) Add(key string, delta int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.counts[key] += delta
+}
+
+func (c *Counters) Get(key string) int64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.counts[key]
+}
+
+func (c *Counters) Copy() *Counters {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	cp := NewCounters()
+	for k, v := range c.counts {
+		cp.counts[k] = v
+	}
+	return cp
+}
+
+func (c *Counters) String() string {
+	return GetCountersMetricResult(c).String()
+}
+
+func GetCountersMetricResult(metric *Counters) CountersMetricResult {
+	metric.lock.Lock()
+	defer metric.lock.Unlock()
+	res := CountersMetricResult{Counts: make(map[string]int64, len(metric.counts))}
+	for k, v := range metric.counts {
+		res.Counts[k] = v
+		res.Total += v
+	}
+	return res
+}
+
+func (o1 CountersMetricResult) Merge(o2 CountersMetricResult) CountersMetricResult {
+	res := CountersMetricResult{Total: o1.Total + o2.Total, Counts: make(map[string]int64, len(o1.Counts)+len(o2.Counts))}
+	for k, v := range o1.Counts {
+		res.Counts[k] += v
+	}
+	for k, v := range o2.Counts {
+		res.Counts[k] += v
+	}
+	return res
+}
+
+func (mr CountersMetricResult) String() string {
+	keys := make([]string, 0, len(mr.Counts))
+	for k := range mr.Counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	counts := make([]string, len(keys))
+	for i, k := range keys {
+		counts[i] = fmt.Sprintf("%s: %d", k, mr.Counts[k])
+	}
+	return fmt.Sprintf("{total: %d, counts: {%s}}", mr.Total, strings.Join(counts, ", "))
+}
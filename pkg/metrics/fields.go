@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/solarisdb/solaris/golibs/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// counterFields are the fields of the COUNTERS results besides the total,
+// which are the names of the gRPC status codes, e.g. Unavailable
+var counterFields = func() map[string]bool {
+	res := make(map[string]bool)
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		res[c.String()] = true
+	}
+	return res
+}()
+
+// ResultField returns the numeric value of the field of a metric result
+// (IntMetricResult, DurationMetricResult etc.), durations are returned in
+// nanoseconds. The following fields are supported:
+//   - INT, DURATION: total, sum, mean
+//   - STRING: total
+//   - RPS: rate, intervalRate
+//   - HISTOGRAM: total, sum, min, max, mean, pNN (e.g. p50, p99, p99.9)
+//   - GAUGE: value, max
+//   - COUNTERS: total or the gRPC status code name (e.g. Unavailable)
+func ResultField(result any, field string) (float64, error) {
+	switch mr := result.(type) {
+	case IntMetricResult:
+		switch field {
+		case "total":
+			return float64(mr.Total), nil
+		case "sum":
+			return float64(mr.Sum), nil
+		case "mean":
+			return float64(mr.Mean), nil
+		}
+	case DurationMetricResult:
+		switch field {
+		case "total":
+			return float64(mr.Total), nil
+		case "sum":
+			return float64(mr.Sum), nil
+		case "mean":
+			return float64(mr.Mean), nil
+		}
+	case StringMetricResult:
+		if field == "total" {
+			return float64(mr.Total), nil
+		}
+	case RateMetricResult:
+		switch field {
+		case "rate":
+			return FromRateMetricResult(mr).Rate(), nil
+		case "intervalRate":
+			return FromRateMetricResult(mr).IntervalRate(), nil
+		}
+	case HistogramMetricResult:
+		switch field {
+		case "total":
+			return float64(mr.Total), nil
+		case "sum":
+			return float64(mr.Sum), nil
+		case "min":
+			return float64(mr.Min), nil
+		case "max":
+			return float64(mr.Max), nil
+		case "mean":
+			return float64(mr.Mean()), nil
+		}
+		if strings.HasPrefix(field, "p") {
+			q, err := strconv.ParseFloat(field[1:], 64)
+			if err == nil && q >= 0 && q <= 100 {
+				return float64(mr.Percentile(q)), nil
+			}
+		}
+	case GaugeMetricResult:
+		switch field {
+		case "value":
+			return float64(mr.Value), nil
+		case "max":
+			return float64(mr.Max), nil
+		}
+	case CountersMetricResult:
+		if field == "total" {
+			return float64(mr.Total), nil
+		}
+		if counterFields[field] {
+			return float64(mr.Counts[field]), nil
+		}
+	default:
+		return 0, fmt.Errorf("unsupported metric result %T: %w", result, errors.ErrInvalid)
+	}
+	return 0, fmt.Errorf("unknown field %q of the metric result %T: %w", field, result, errors.ErrInvalid)
+}
+
+// CounterFields returns the sorted names of the fields of the COUNTERS
+// results besides the total
+func CounterFields() []string {
+	res := make([]string, 0, len(counterFields))
+	for f := range counterFields {
+		res = append(res, f)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+type (
+	// Gauge is a metric holding the current value, which could be
+	// increased/decreased, and the max value it ever had
+	Gauge struct {
+		value atomic.Int64
+		max   atomic.Int64
+	}
+
+	GaugeMetricResult struct {
+		Value int64 `yaml:"value" json:"value"`
+		Max   int64 `yaml:"max" json:"max"`
+	}
+)
+
+func NewGauge() *Gauge {
+	return new(Gauge)
+}
+
+func (g *Gauge) Add(delta int64) {
+	g.updateMax(g.value.Add(delta))
+}
+
+func (g *Gauge) Set(value int64) {
+	g.value.Store(value)
+	g.updateMax(value)
+}
+
+func (g *Gauge) updateMax(value int64) {
+	for max := g.max.Load(); value > max; max = g.max.Load() {
+		if g.max.CompareAndSwap(max, value) {
+			return
+		}
+	}
+}
+
+func (g *Gauge) Value() int64 {
+	return g.value.Load()
+}
+
+func (g *Gauge) Max() int64 {
+	return g.max.Load()
+}
+
+func (g *Gauge) Copy() *Gauge {
+	var cp Gauge
+	cp.value.Store(g.value.Load())
+	cp.max.Store(g.max.Load())
+	return &cp
+}
+
+func (g *Gauge) String() string {
+	return GetGaugeMetricResult(g).String()
+}
+
+func GetGaugeMetricResult(metric *Gauge) GaugeMetricResult {
+	return GaugeMetricResult{Value: metric.Value(), Max: metric.Max()}
+}
+
+// Merge sums the gauges of the cluster nodes, the max of the sum is
+// the upper estimate as the nodes could reach their max at different times
+func (o1 GaugeMetricResult) Merge(o2 GaugeMetricResult) GaugeMetricResult {
+	return GaugeMetricResult{Value: o1.Value + o2.Value, Max: o1.Max + o2.Max}
+}
+
+func (mr GaugeMetricResult) String() string {
+	return fmt.Sprintf("{value: %d, max: %d}", mr.Value, mr.Max)
+}
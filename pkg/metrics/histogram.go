@@ -0,0 +1,218 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/solarisdb/solaris/golibs/container"
+)
+
+type (
+	// Histogram is an HDR-style histogram of int64 values (usually nanoseconds).
+	// The values are counted in log-linear buckets: every power of 2 range is
+	// split into 2^histSubBucketBits equal sub-buckets, so any percentile is
+	// reported with the relative error less than 1% while the memory footprint
+	// does not depend on the number of recorded values.
+	Histogram struct {
+		counts []int64
+		total  int64
+		sum    int64
+		min    int64
+		max    int64
+
+		lock sync.Mutex
+	}
+
+	// HistogramBucket is a non-empty bucket of the histogram
+	HistogramBucket struct {
+		Index int   `yaml:"i" json:"i"`
+		Count int64 `yaml:"c" json:"c"`
+	}
+
+	HistogramMetricResult struct {
+		Total   int64             `yaml:"total" json:"total"`
+		Sum     int64             `yaml:"sum" json:"sum"`
+		Min     int64             `yaml:"min" json:"min"`
+		Max     int64             `yaml:"max" json:"max"`
+		Buckets []HistogramBucket `yaml:"buckets,omitempty" json:"buckets,omitempty"`
+	}
+)
+
+const (
+	histSubBucketBits  = 7
+	histSubBucketCount = 1 << histSubBucketBits
+)
+
+// HistogramPercentiles are the percentiles reported for every histogram
+var HistogramPercentiles = []float64{50, 90, 95, 99, 99.9}
+
+func NewHistogram() *Histogram {
+	return new(Histogram)
+}
+
+// Add records the value, negative values are counted as 0
+func (h *Histogram) Add(value int64) {
+	if value < 0 {
+		value = 0
+	}
+	idx := histBucketIndex(value)
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if idx >= len(h.counts) {
+		h.counts = append(h.counts, make([]int64, idx-len(h.counts)+1)...)
+	}
+	h.counts[idx]++
+	if h.total == 0 || value < h.min {
+		h.min = value
+	}
+	if value > h.max {
+		h.max = value
+	}
+	h.total++
+	h.sum += value
+}
+
+func (h *Histogram) Total() int64 {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.total
+}
+
+func (h *Histogram) Copy() *Histogram {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return &Histogram{
+		counts: container.SliceCopy(h.counts),
+		total:  h.total,
+		sum:    h.sum,
+		min:    h.min,
+		max:    h.max,
+	}
+}
+
+// histBucketIndex returns the index of the bucket the value v belongs to.
+// Values less than 2*histSubBucketCount are counted exactly, the bigger ones
+// are counted with the precision of the histSubBucketBits most significant bits.
+func histBucketIndex(v int64) int {
+	if v < 2*histSubBucketCount {
+		return int(v)
+	}
+	shift := bits.Len64(uint64(v)) - histSubBucketBits - 1
+	return shift*histSubBucketCount + int(v>>shift)
+}
+
+// histBucketBounds returns the lowest and the highest values of the bucket idx
+func histBucketBounds(idx int) (int64, int64) {
+	if idx < 2*histSubBucketCount {
+		return int64(idx), int64(idx)
+	}
+	shift := idx/histSubBucketCount - 1
+	mantissa := int64(idx - shift*histSubBucketCount)
+	low := mantissa << shift
+	return low, low + (int64(1) << shift) - 1
+}
+
+func GetHistogramMetricResult(metric *Histogram) HistogramMetricResult {
+	metric.lock.Lock()
+	defer metric.lock.Unlock()
+	var metricResult HistogramMetricResult
+	metricResult.Total = metric.total
+	metricResult.Sum = metric.sum
+	metricResult.Min = metric.min
+	metricResult.Max = metric.max
+	for idx, cnt := range metric.counts {
+		if cnt > 0 {
+			metricResult.Buckets = append(metricResult.Buckets, HistogramBucket{Index: idx, Count: cnt})
+		}
+	}
+	return metricResult
+}
+
+func FromHistogramMetricResult(result HistogramMetricResult) *Histogram {
+	var h Histogram
+	h.total = result.Total
+	h.sum = result.Sum
+	h.min = result.Min
+	h.max = result.Max
+	for _, b := range result.Buckets {
+		if b.Index >= len(h.counts) {
+			h.counts = append(h.counts, make([]int64, b.Index-len(h.counts)+1)...)
+		}
+		h.counts[b.Index] += b.Count
+	}
+	return &h
+}
+
+// Merge returns the histogram containing the values of the both histograms,
+// the percentiles of the result are calculated over all the values, so it is
+// not an average of the o1 and o2 percentiles.
+func (o1 HistogramMetricResult) Merge(o2 HistogramMetricResult) HistogramMetricResult {
+	if o1.Total == 0 {
+		return o2
+	}
+	if o2.Total == 0 {
+		return o1
+	}
+	var res HistogramMetricResult
+	res.Total = o1.Total + o2.Total
+	res.Sum = o1.Sum + o2.Sum
+	res.Min = min(o1.Min, o2.Min)
+	res.Max = max(o1.Max, o2.Max)
+	i, j := 0, 0
+	for i < len(o1.Buckets) || j < len(o2.Buckets) {
+		switch {
+		case j == len(o2.Buckets) || (i < len(o1.Buckets) && o1.Buckets[i].Index < o2.Buckets[j].Index):
+			res.Buckets = append(res.Buckets, o1.Buckets[i])
+			i++
+		case i == len(o1.Buckets) || o2.Buckets[j].Index < o1.Buckets[i].Index:
+			res.Buckets = append(res.Buckets, o2.Buckets[j])
+			j++
+		default:
+			res.Buckets = append(res.Buckets, HistogramBucket{Index: o1.Buckets[i].Index, Count: o1.Buckets[i].Count + o2.Buckets[j].Count})
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+// Percentile returns the value below which q percent of the values fall,
+// q is in the range [0..100]
+func (mr HistogramMetricResult) Percentile(q float64) time.Duration {
+	if mr.Total == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(q / 100 * float64(mr.Total)))
+	rank = min(max(rank, 1), mr.Total)
+	var seen int64
+	for _, b := range mr.Buckets {
+		seen += b.Count
+		if seen >= rank {
+			_, high := histBucketBounds(b.Index)
+			return time.Duration(min(max(high, mr.Min), mr.Max))
+		}
+	}
+	return time.Duration(mr.Max)
+}
+
+func (mr HistogramMetricResult) Mean() time.Duration {
+	if mr.Total == 0 {
+		return 0
+	}
+	return time.Duration(int64(float64(mr.Sum) / float64(mr.Total)))
+}
+
+func (mr HistogramMetricResult) String() string {
+	res := fmt.Sprintf("{total: %d, min: %s, mean: %s", mr.Total, time.Duration(mr.Min).Round(time.Microsecond), mr.Mean().Round(time.Microsecond))
+	for _, q := range HistogramPercentiles {
+		res += fmt.Sprintf(", p%g: %s", q, mr.Percentile(q).Round(time.Microsecond))
+	}
+	return res + fmt.Sprintf(", max: %s}", time.Duration(mr.Max).Round(time.Microsecond))
+}
+
+func (h *Histogram) String() string {
+	return GetHistogramMetricResult(h).String()
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestHistogram_Percentiles(t *testing.T) {
+	h := NewHistogram()
+	for i := 1; i <= 10000; i++ {
+		h.Add(int64(i) * int64(time.Microsecond))
+	}
+	result := GetHistogramMetricResult(h)
+	assert.Equal(t, int64(10000), result.Total)
+	assert.Equal(t, time.Microsecond, time.Duration(result.Min))
+	assert.Equal(t, 10*time.Millisecond, time.Duration(result.Max))
+	for _, q := range HistogramPercentiles {
+		expected := float64(q) / 100 * float64(10*time.Millisecond)
+		assert.InEpsilon(t, expected, float64(result.Percentile(q)), 0.01)
+	}
+	assert.Equal(t, 10*time.Millisecond, result.Percentile(100))
+}
+
+func TestHistogramMetricResult_SerDeserMerge(t *testing.T) {
+	h1 := NewHistogram()
+	h2 := NewHistogram()
+	for i := 0; i < 1000; i++ {
+		h1.Add(int64(time.Millisecond))
+		h2.Add(int64(100 * time.Millisecond))
+	}
+	raw, err := json.Marshal(GetHistogramMetricResult(h2))
+	assert.NoError(t, err)
+	var deserResult HistogramMetricResult
+	assert.NoError(t, json.Unmarshal(raw, &deserResult))
+	assert.Equal(t, GetHistogramMetricResult(h2), deserResult)
+
+	merged := GetHistogramMetricResult(h1).Merge(deserResult)
+	assert.Equal(t, int64(2000), merged.Total)
+	assert.InEpsilon(t, float64(time.Millisecond), float64(merged.Percentile(50)), 0.01)
+	assert.InEpsilon(t, float64(100*time.Millisecond), float64(merged.Percentile(99)), 0.01)
+	assert.Equal(t, GetHistogramMetricResult(FromHistogramMetricResult(merged)), merged)
+}
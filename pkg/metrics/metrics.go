@@ -362,7 +362,7 @@ func (mr RateMetricResult) String() string {
 	return FromRateMetricResult(mr).String()
 }
 
-func (r Rate) String() string {
+func (r *Rate) String() string {
 	var scaleStr string
 	switch r.scale {
 	case time.Second:
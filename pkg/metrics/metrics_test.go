@@ -21,3 +21,28 @@ func TestRateMetricResult_SerDeser(t *testing.T) {
 	merged := result.Merge(deserResult)
 	assert.Equal(t, FromRateMetricResult(merged).Rate(), rate.Rate())
 }
+
+func TestCountersMetricResult(t *testing.T) {
+	c := NewCounters()
+	c.Add("Unavailable", 1)
+	c.Add("Unavailable", 2)
+	c.Add("Internal", 1)
+	assert.Equal(t, int64(3), c.Get("Unavailable"))
+	result := GetCountersMetricResult(c)
+	assert.Equal(t, CountersMetricResult{Total: 4, Counts: map[string]int64{"Unavailable": 3, "Internal": 1}}, result)
+	assert.Equal(t, "{total: 4, counts: {Internal: 1, Unavailable: 3}}", result.String())
+
+	merged := result.Merge(CountersMetricResult{Total: 1, Counts: map[string]int64{"Canceled": 1}})
+	assert.Equal(t, int64(5), merged.Total)
+	v, err := ResultField(merged, "Unavailable")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3), v)
+	v, err = ResultField(merged, "total")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5), v)
+	v, err = ResultField(merged, "DeadlineExceeded")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), v)
+	_, err = ResultField(merged, "Unavailible")
+	assert.Error(t, err)
+}
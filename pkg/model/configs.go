@@ -13,12 +13,19 @@ type (
 	Config struct {
 		Log LoggingConfig `yaml:"log" mapstructure:"log" json:"log"`
 
+		Summary *SummaryConfig `yaml:"summary,omitempty" json:"summary,omitempty"`
+
+		Exporter *ExporterConfig `yaml:"exporter,omitempty" json:"exporter,omitempty"`
+
 		Tests []Test `yaml:"tests"  json:"tests"`
 	}
 
 	Test struct {
 		Name     string   `yaml:"name" json:"name"`
 		Scenario Scenario `yaml:"scenario" json:"scenario"`
+		// Thresholds contains the assertions over the test metrics, which are
+		// checked when the scenario is finished, e.g. "AppendTimeout.mean < 50ms"
+		Thresholds []string `yaml:"thresholds,omitempty" json:"thresholds,omitempty"`
 	}
 
 	Scenario struct {
@@ -30,6 +37,21 @@ type (
 		RawCfg json.RawMessage
 	}
 
+	SummaryConfig struct {
+		// Path is the file the JSON summary of the run is written to,
+		// the summary is not written if the path is empty
+		Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	}
+
+	ExporterConfig struct {
+		// Address is the address (e.g. ":9100") the metrics of the running
+		// tests are exposed on in the Prometheus text format, the metrics
+		// are not exposed if the address is empty
+		Address string `yaml:"address,omitempty" json:"address,omitempty"`
+		// Path is the HTTP path of the metrics, "/metrics" by default
+		Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	}
+
 	LoggingConfig struct {
 		// Level describes desired logging level
 		Level string `yaml:"level" json:"level"`
@@ -0,0 +1,135 @@
+package payload
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+
+	"github.com/solarisdb/solaris/golibs/errors"
+)
+
+type (
+	// Verifier checks the payloads sealed by the Seal function, the
+	// payloads should be added in the order they are read from the log.
+	// The Verifier is not safe for concurrent use.
+	Verifier struct {
+		writers map[string]*writerState
+		result  VerifyResult
+	}
+
+	// VerifyResult contains the numbers of the records of the every kind
+	VerifyResult struct {
+		// Records is the total number of the verified records
+		Records int64 `json:"records"`
+		// Missing is the number of the records sealed by the seen writers,
+		// but not found. The writers with no records found cannot be detected.
+		Missing int64 `json:"missing"`
+		// Duplicated is the number of the records seen more than once
+		Duplicated int64 `json:"duplicated"`
+		// Reordered is the number of the records found after the records
+		// of the same writer with the greater sequence numbers
+		Reordered int64 `json:"reordered"`
+		// Corrupted is the number of the records with no valid seal
+		Corrupted int64 `json:"corrupted"`
+	}
+
+	writerState struct {
+		total int64
+		seen  []bool
+		max   int64
+	}
+)
+
+// sealMagic starts every sealed payload
+const sealMagic = "#pt|"
+
+// Seal returns the payload with the header containing the writer ID, the
+// sequence number of the payload among the total payloads of the writer
+// and the checksum of the payload and the header fields
+func Seal(writer string, seq, total int64, payload []byte) []byte {
+	fields := fmt.Sprintf("%s|%d|%d|", writer, seq, total)
+	sum := crc32.Update(crc32.ChecksumIEEE([]byte(fields)), crc32.IEEETable, payload)
+	sealed := make([]byte, 0, len(sealMagic)+len(fields)+9+len(payload))
+	sealed = append(sealed, sealMagic...)
+	sealed = append(sealed, fields...)
+	sealed = fmt.Appendf(sealed, "%08x|", sum)
+	return append(sealed, payload...)
+}
+
+// Unseal checks the sealed payload and returns its header fields
+func Unseal(sealed []byte) (writer string, seq, total int64, err error) {
+	if !bytes.HasPrefix(sealed, []byte(sealMagic)) {
+		return "", 0, 0, fmt.Errorf("payload is not sealed: %w", errors.ErrInvalid)
+	}
+	parts := bytes.SplitN(sealed[len(sealMagic):], []byte("|"), 5)
+	if len(parts) != 5 {
+		return "", 0, 0, fmt.Errorf("payload header is truncated: %w", errors.ErrInvalid)
+	}
+	seq, err1 := strconv.ParseInt(string(parts[1]), 10, 64)
+	total, err2 := strconv.ParseInt(string(parts[2]), 10, 64)
+	sum, err3 := strconv.ParseUint(string(parts[3]), 16, 32)
+	if err1 != nil || err2 != nil || err3 != nil || seq < 0 || seq >= total {
+		return "", 0, 0, fmt.Errorf("payload header is malformed: %w", errors.ErrInvalid)
+	}
+	fieldsLen := len(parts[0]) + len(parts[1]) + len(parts[2]) + 3
+	fields := sealed[len(sealMagic) : len(sealMagic)+fieldsLen]
+	if crc32.Update(crc32.ChecksumIEEE(fields), crc32.IEEETable, parts[4]) != uint32(sum) {
+		return "", 0, 0, fmt.Errorf("payload checksum mismatch: %w", errors.ErrInvalid)
+	}
+	return string(parts[0]), seq, total, nil
+}
+
+// NewVerifier returns the new Verifier
+func NewVerifier() *Verifier {
+	return &Verifier{writers: map[string]*writerState{}}
+}
+
+// Add verifies the next payload read from the log
+func (v *Verifier) Add(sealed []byte) {
+	v.result.Records++
+	writer, seq, total, err := Unseal(sealed)
+	if err != nil {
+		v.result.Corrupted++
+		return
+	}
+	ws, ok := v.writers[writer]
+	if !ok {
+		ws = &writerState{total: total, seen: make([]bool, total), max: -1}
+		v.writers[writer] = ws
+	}
+	switch {
+	case ws.total != total:
+		v.result.Corrupted++
+	case ws.seen[seq]:
+		v.result.Duplicated++
+	default:
+		ws.seen[seq] = true
+		if seq < ws.max {
+			v.result.Reordered++
+		}
+		ws.max = max(ws.max, seq)
+	}
+}
+
+// Result returns the verification result of the payloads added so far
+func (v *Verifier) Result() VerifyResult {
+	res := v.result
+	for _, ws := range v.writers {
+		for _, seen := range ws.seen {
+			if !seen {
+				res.Missing++
+			}
+		}
+	}
+	return res
+}
+
+// Err returns the error if any record is missing, duplicated, reordered or corrupted
+func (r VerifyResult) Err() error {
+	if r.Missing+r.Duplicated+r.Reordered+r.Corrupted == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d records are broken (missing=%d, duplicated=%d, reordered=%d, corrupted=%d): %w",
+		r.Missing+r.Duplicated+r.Reordered+r.Corrupted, r.Records, r.Missing, r.Duplicated, r.Reordered, r.Corrupted, errors.ErrDataLoss)
+}
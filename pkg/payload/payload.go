@@ -0,0 +1,305 @@
+package payload
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/utils"
+	"github.com/solarisdb/solaris/golibs/container"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/ulidutils"
+)
+
+type (
+	// Generator returns the message payloads, it is safe for concurrent use
+	Generator interface {
+		Next() []byte
+	}
+
+	// Config describes the payload generator
+	Config struct {
+		// Type is one of the generator types, TypeConstant by default
+		Type string `yaml:"type,omitempty" json:"type,omitempty"`
+		// Size is the payload size distribution function (see utils.ParseDistribution),
+		// e.g. "uniform(100, 1000)", the message size is used if empty. The size
+		// is ignored by the TypeJSON and TypeFile generators.
+		Size string `yaml:"size,omitempty" json:"size,omitempty"`
+		// CompressionRatio is the approximate ratio of the original to the
+		// compressed size of the TypeText payloads, 1 (incompressible) by default
+		CompressionRatio float64 `yaml:"compressionRatio,omitempty" json:"compressionRatio,omitempty"`
+		// Template is the JSON document of the TypeJSON generator with the
+		// placeholders replaced by the random values on every payload:
+		// {{int(min,max)}}, {{string(len)}}, {{choice(a|b|c)}}, {{uuid}}, {{timestamp}}
+		Template string `yaml:"template,omitempty" json:"template,omitempty"`
+		// File is the file the TypeFile generator replays line by line
+		File string `yaml:"file,omitempty" json:"file,omitempty"`
+		// PoolSize is the number of the payloads generated in advance, the
+		// payloads are drawn from the pool randomly. Zero means every payload
+		// is freshly generated.
+		PoolSize int `yaml:"poolSize,omitempty" json:"poolSize,omitempty"`
+	}
+
+	constantGenerator struct {
+		size    utils.Distribution
+		payload []byte
+	}
+
+	randomGenerator struct {
+		size utils.Distribution
+	}
+
+	textGenerator struct {
+		size        utils.Distribution
+		randomShare float64
+	}
+
+	templateGenerator struct {
+		parts []templatePart
+	}
+
+	// templatePart is either the literal text or the placeholder value generator
+	templatePart struct {
+		text  string
+		value func(b *bytes.Buffer)
+	}
+
+	fileGenerator struct {
+		lines [][]byte
+		idx   atomic.Uint64
+	}
+
+	poolGenerator struct {
+		pool [][]byte
+	}
+)
+
+const (
+	TypeConstant = "constant"
+	TypeRandom   = "random"
+	TypeText     = "text"
+	TypeJSON     = "json"
+	TypeFile     = "file"
+)
+
+const (
+	printableChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 "
+	// textFiller is repeated in the compressible part of the text payloads
+	textFiller = "the quick brown fox jumps over the lazy dog "
+)
+
+var placeholderRegexp = regexp.MustCompile(`\{\{\s*(\w+)\s*(?:\(([^)]*)\))?\s*}}`)
+
+// New returns the generator described by the config, the defaultSize is used
+// if the config size is not set. The nil config means the constant payload
+// of the defaultSize.
+func New(cfg *Config, defaultSize int) (Generator, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	size := func() int64 { return int64(defaultSize) }
+	if len(cfg.Size) > 0 {
+		var err error
+		if size, err = utils.ParseDistribution(cfg.Size); err != nil {
+			return nil, fmt.Errorf("failed to parse payload size: %w", err)
+		}
+	}
+	var gen Generator
+	switch cfg.Type {
+	case "", TypeConstant:
+		if len(cfg.Size) == 0 {
+			// the same payload for all the messages, nothing to pool
+			payload := make([]byte, defaultSize)
+			container.SliceFill(payload, 'z')
+			return &constantGenerator{payload: payload}, nil
+		}
+		gen = &constantGenerator{size: size}
+	case TypeRandom:
+		gen = &randomGenerator{size: size}
+	case TypeText:
+		ratio := cfg.CompressionRatio
+		if ratio < 1 {
+			ratio = 1
+		}
+		gen = &textGenerator{size: size, randomShare: 1 / ratio}
+	case TypeJSON:
+		tg, err := NewTemplate(cfg.Template)
+		if err != nil {
+			return nil, err
+		}
+		gen = tg
+	case TypeFile:
+		fg, err := newFileGenerator(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		return fg, nil
+	default:
+		return nil, fmt.Errorf("unknown payload type %q: %w", cfg.Type, errors.ErrInvalid)
+	}
+	if cfg.PoolSize > 0 {
+		gen = newPoolGenerator(gen, cfg.PoolSize)
+	}
+	return gen, nil
+}
+
+func (g *constantGenerator) Next() []byte {
+	if g.size == nil {
+		return g.payload
+	}
+	payload := make([]byte, max(g.size(), 0))
+	container.SliceFill(payload, 'z')
+	return payload
+}
+
+func (g *randomGenerator) Next() []byte {
+	payload := make([]byte, max(g.size(), 0))
+	for i := 0; i < len(payload); i += 8 {
+		v := rand.Uint64()
+		for j := i; j < len(payload) && j < i+8; j++ {
+			payload[j] = byte(v)
+			v >>= 8
+		}
+	}
+	return payload
+}
+
+// Next returns the text of the random printable chars followed by the
+// repeated filler, so the compressors squeeze the filler part only
+func (g *textGenerator) Next() []byte {
+	payload := make([]byte, max(g.size(), 0))
+	randomLen := int(float64(len(payload)) * g.randomShare)
+	for i := 0; i < randomLen; i++ {
+		payload[i] = printableChars[rand.IntN(len(printableChars))]
+	}
+	for i := randomLen; i < len(payload); i++ {
+		payload[i] = textFiller[(i-randomLen)%len(textFiller)]
+	}
+	return payload
+}
+
+// NewTemplate returns the generator of the texts produced from the template
+// by replacing the placeholders (see Config.Template) with the random values
+func NewTemplate(template string) (Generator, error) {
+	if len(template) == 0 {
+		return nil, fmt.Errorf("template is empty: %w", errors.ErrInvalid)
+	}
+	g := new(templateGenerator)
+	last := 0
+	for _, m := range placeholderRegexp.FindAllStringSubmatchIndex(template, -1) {
+		g.parts = append(g.parts, templatePart{text: template[last:m[0]]})
+		name := template[m[2]:m[3]]
+		var args string
+		if m[4] >= 0 {
+			args = template[m[4]:m[5]]
+		}
+		value, err := placeholderValue(name, args)
+		if err != nil {
+			return nil, fmt.Errorf("invalid placeholder %q: %w", template[m[0]:m[1]], err)
+		}
+		g.parts = append(g.parts, templatePart{value: value})
+		last = m[1]
+	}
+	g.parts = append(g.parts, templatePart{text: template[last:]})
+	return g, nil
+}
+
+func placeholderValue(name, args string) (func(b *bytes.Buffer), error) {
+	switch name {
+	case "int":
+		bounds := strings.Split(args, ",")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("int(min,max) expected: %w", errors.ErrInvalid)
+		}
+		min, err1 := strconv.ParseInt(strings.TrimSpace(bounds[0]), 10, 64)
+		max, err2 := strconv.ParseInt(strings.TrimSpace(bounds[1]), 10, 64)
+		if err1 != nil || err2 != nil || min > max {
+			return nil, fmt.Errorf("int(min,max) bounds are invalid: %w", errors.ErrInvalid)
+		}
+		return func(b *bytes.Buffer) {
+			b.WriteString(strconv.FormatInt(min+rand.Int64N(max-min+1), 10))
+		}, nil
+	case "string":
+		n, err := strconv.Atoi(strings.TrimSpace(args))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("string(len) length is invalid: %w", errors.ErrInvalid)
+		}
+		return func(b *bytes.Buffer) {
+			for i := 0; i < n; i++ {
+				b.WriteByte(printableChars[rand.IntN(len(printableChars)-1)])
+			}
+		}, nil
+	case "choice":
+		choices := strings.Split(args, "|")
+		return func(b *bytes.Buffer) {
+			b.WriteString(choices[rand.IntN(len(choices))])
+		}, nil
+	case "uuid":
+		return func(b *bytes.Buffer) {
+			b.WriteString(ulidutils.NewUUID().String())
+		}, nil
+	case "timestamp":
+		return func(b *bytes.Buffer) {
+			b.WriteString(time.Now().UTC().Format(time.RFC3339Nano))
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown placeholder %s: %w", name, errors.ErrInvalid)
+}
+
+func (g *templateGenerator) Next() []byte {
+	var b bytes.Buffer
+	for _, p := range g.parts {
+		if p.value != nil {
+			p.value(&b)
+		} else {
+			b.WriteString(p.text)
+		}
+	}
+	return b.Bytes()
+}
+
+func newFileGenerator(file string) (*fileGenerator, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open payload file: %w", err)
+	}
+	defer f.Close()
+	g := new(fileGenerator)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		g.lines = append(g.lines, bytes.Clone(scanner.Bytes()))
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read payload file: %w", err)
+	}
+	if len(g.lines) == 0 {
+		return nil, fmt.Errorf("payload file %s is empty: %w", file, errors.ErrInvalid)
+	}
+	return g, nil
+}
+
+// Next returns the lines of the file one by one, from the beginning when
+// all the lines are returned
+func (g *fileGenerator) Next() []byte {
+	return g.lines[(g.idx.Add(1)-1)%uint64(len(g.lines))]
+}
+
+func newPoolGenerator(gen Generator, size int) *poolGenerator {
+	g := &poolGenerator{pool: make([][]byte, size)}
+	for i := range g.pool {
+		g.pool[i] = gen.Next()
+	}
+	return g
+}
+
+func (g *poolGenerator) Next() []byte {
+	return g.pool[rand.IntN(len(g.pool))]
+}
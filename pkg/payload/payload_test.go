@@ -0,0 +1,155 @@
+package payload
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_Sizes(t *testing.T) {
+	gen, err := New(nil, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, bytes.Repeat([]byte{'z'}, 100), gen.Next())
+
+	for _, typ := range []string{TypeConstant, TypeRandom, TypeText} {
+		gen, err = New(&Config{Type: typ, Size: "uniform(10, 20)"}, 100)
+		assert.NoError(t, err)
+		for i := 0; i < 100; i++ {
+			size := len(gen.Next())
+			assert.GreaterOrEqual(t, size, 10)
+			assert.Less(t, size, 20)
+		}
+	}
+
+	gen, err = New(&Config{Type: TypeRandom, PoolSize: 3}, 10)
+	assert.NoError(t, err)
+	pool := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		pool[string(gen.Next())] = true
+	}
+	assert.LessOrEqual(t, len(pool), 3)
+
+	_, err = New(&Config{Type: "unknown"}, 10)
+	assert.Error(t, err)
+	_, err = New(&Config{Size: "uniform(20, 10)"}, 10)
+	assert.Error(t, err)
+}
+
+func TestNew_TextCompressionRatio(t *testing.T) {
+	for _, ratio := range []float64{1, 4} {
+		gen, err := New(&Config{Type: TypeText, CompressionRatio: ratio}, 64*1024)
+		assert.NoError(t, err)
+		payload := gen.Next()
+		var b bytes.Buffer
+		w := gzip.NewWriter(&b)
+		_, _ = w.Write(payload)
+		assert.NoError(t, w.Close())
+		// the random printable chars may be compressed a bit by themselves
+		actual := float64(len(payload)) / float64(b.Len())
+		assert.Greater(t, actual, ratio*0.9)
+		assert.Less(t, actual, ratio*2)
+	}
+}
+
+func TestNew_JSON(t *testing.T) {
+	gen, err := New(&Config{
+		Type:     TypeJSON,
+		Template: `{"id": "{{uuid}}", "n": {{int(1, 5)}}, "s": "{{string(8)}}", "c": "{{choice(a|b)}}", "ts": "{{timestamp}}"}`,
+	}, 0)
+	assert.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		var doc struct {
+			ID string
+			N  int
+			S  string
+			C  string
+		}
+		payload := gen.Next()
+		assert.True(t, json.Valid(payload), string(payload))
+		assert.NoError(t, json.Unmarshal(payload, &doc))
+		assert.Len(t, doc.ID, 36)
+		assert.GreaterOrEqual(t, doc.N, 1)
+		assert.LessOrEqual(t, doc.N, 5)
+		assert.Len(t, doc.S, 8)
+		assert.Contains(t, []string{"a", "b"}, doc.C)
+	}
+
+	cond, err := NewTemplate("payload LIKE '%{{choice(a|b)}}%'")
+	assert.NoError(t, err)
+	assert.Contains(t, []string{"payload LIKE '%a%'", "payload LIKE '%b%'"}, string(cond.Next()))
+
+	_, err = New(&Config{Type: TypeJSON, Template: `{"n": {{int(5)}}}`}, 0)
+	assert.Error(t, err)
+	_, err = New(&Config{Type: TypeJSON, Template: `{"n": {{float}}}`}, 0)
+	assert.Error(t, err)
+}
+
+func TestNew_File(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "payload.txt")
+	assert.NoError(t, os.WriteFile(file, []byte("line1\nline2\nline3\n"), 0644))
+	gen, err := New(&Config{Type: TypeFile, File: file}, 0)
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, "line1", string(gen.Next()))
+		assert.Equal(t, "line2", string(gen.Next()))
+		assert.Equal(t, "line3", string(gen.Next()))
+	}
+
+	_, err = New(&Config{Type: TypeFile, File: filepath.Join(t.TempDir(), "absent")}, 0)
+	assert.Error(t, err)
+}
+
+func TestVerifier(t *testing.T) {
+	v := NewVerifier()
+	for i := int64(0); i < 10; i++ {
+		v.Add(Seal("w1", i, 10, []byte("payload")))
+	}
+	assert.NoError(t, v.Result().Err())
+	assert.Equal(t, int64(10), v.Result().Records)
+
+	v = NewVerifier()
+	for _, seq := range []int64{0, 2, 1, 2} {
+		v.Add(Seal("w1", seq, 5, []byte("payload")))
+	}
+	corrupted := Seal("w2", 0, 1, []byte("payload"))
+	corrupted[len(corrupted)-1] = 'x'
+	v.Add(corrupted)
+	v.Add([]byte("not sealed"))
+	res := v.Result()
+	assert.Equal(t, VerifyResult{Records: 6, Missing: 2, Duplicated: 1, Reordered: 1, Corrupted: 2}, res)
+	assert.Error(t, res.Err())
+
+	writer, seq, total, err := Unseal(Seal("w|3", 1, 2, nil))
+	assert.Error(t, err)
+	writer, seq, total, err = Unseal(Seal("w3", 1, 2, []byte("a|b")))
+	assert.NoError(t, err)
+	assert.Equal(t, "w3", writer)
+	assert.Equal(t, int64(1), seq)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestStamp(t *testing.T) {
+	now := time.Now()
+	ts, ok := StampTime(Stamp(now, []byte("payload")))
+	assert.True(t, ok)
+	assert.True(t, now.Equal(ts))
+
+	sealed := Seal("w1", 0, 1, Stamp(now, []byte("payload")))
+	ts, ok = StampTime(sealed)
+	assert.True(t, ok)
+	assert.True(t, now.Equal(ts))
+	v := NewVerifier()
+	v.Add(sealed)
+	assert.NoError(t, v.Result().Err())
+
+	_, ok = StampTime([]byte("payload"))
+	assert.False(t, ok)
+	_, ok = StampTime(Seal("w1", 0, 1, []byte("payload")))
+	assert.False(t, ok)
+}
@@ -0,0 +1,45 @@
+package payload
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+)
+
+// stampMagic starts the time stamped payloads
+const stampMagic = "#ts|"
+
+// Stamp returns the payload prefixed by the time, the stamped payload
+// may be sealed then (see Seal)
+func Stamp(t time.Time, payload []byte) []byte {
+	stamped := make([]byte, 0, len(stampMagic)+20+len(payload))
+	stamped = append(stamped, stampMagic...)
+	stamped = strconv.AppendInt(stamped, t.UnixNano(), 10)
+	stamped = append(stamped, '|')
+	return append(stamped, payload...)
+}
+
+// StampTime returns the time of the stamped (and probably sealed) payload,
+// or false if the payload is not stamped
+func StampTime(payload []byte) (time.Time, bool) {
+	if bytes.HasPrefix(payload, []byte(sealMagic)) {
+		parts := bytes.SplitN(payload[len(sealMagic):], []byte("|"), 5)
+		if len(parts) != 5 {
+			return time.Time{}, false
+		}
+		payload = parts[4]
+	}
+	if !bytes.HasPrefix(payload, []byte(stampMagic)) {
+		return time.Time{}, false
+	}
+	payload = payload[len(stampMagic):]
+	end := bytes.IndexByte(payload, '|')
+	if end < 0 {
+		return time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(string(payload[:end]), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
@@ -0,0 +1,282 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/utils"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	assertRunner struct {
+		exec *assertExecutor
+		name string
+	}
+	assertExecutor struct {
+		name     string
+		Registry *Registry      `inject:""`
+		Logger   logging.Logger `inject:""`
+	}
+
+	AssertCfg struct {
+		// Conditions contains the assertions in the form "<metric>.<field> <op> <value>",
+		// e.g. "AppendTimeout.mean < 50ms", "AppendBytesInSec.rate > 100MB", "errors.total == 0"
+		Conditions []string `yaml:"conditions" json:"conditions"`
+	}
+
+	// Assertion is a parsed condition over a field of a metric result,
+	// the value is parsed by the type of the metric field when it is checked
+	Assertion struct {
+		Expr   string
+		Metric string
+		Field  string
+		Op     string
+		Value  string
+	}
+
+	// MetricLookup returns the metric result (IntMetricResult, DurationMetricResult etc.) by the metric name
+	MetricLookup func(name string) (any, bool)
+
+	valueUnit int
+)
+
+const (
+	AssertRunName = "assert"
+	// ErrorsMetricName is the name of the pseudo metric which counts the skipped errors
+	ErrorsMetricName = "errors"
+)
+
+const (
+	unitNone valueUnit = iota
+	unitDuration
+	unitBytes
+)
+
+var (
+	assertionRegexp   = regexp.MustCompile(`^\s*(\S+)\s*(<=|>=|==|!=|<|>)\s*(\S+)\s*$`)
+	metricFieldRegexp = regexp.MustCompile(`^(total|sum|mean|min|max|rate|intervalRate|value|p\d+(\.\d+)?|` + strings.Join(metrics.CounterFields(), "|") + `)$`)
+	bytesSuffixes     = []string{"TB", "GB", "MB", "KB", "B"}
+	sizeSuffixes      = []string{"T", "G", "M", "K"}
+)
+
+func NewAssertRunner(exec *assertExecutor, prefix string) ScenarioRunner {
+	return &assertRunner{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), GetRunnerIndex())}
+}
+
+func NewAssertExecutor() ScenarioExecutor {
+	return &assertExecutor{name: AssertRunName}
+}
+
+func (r *assertExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *assertExecutor) Name() string {
+	return r.name
+}
+
+func (r *assertExecutor) New(prefix string) ScenarioRunner {
+	return NewAssertRunner(r, prefix)
+}
+
+func (r *assertRunner) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *assertRunner) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan ScenarioResult) {
+	doneCh = make(chan ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed)}
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[AssertCfg](config)
+	if err != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to parse scenario config %w", err)}
+		return
+	}
+	doneCh <- &staticScenarioResult{ctx, CheckAssertions(cfg.Conditions, ContextMetricLookup(ctx))}
+	return
+}
+
+// CheckAssertions parses and checks all the conditions, it returns an error
+// describing every failed condition or nil if all of them hold.
+func CheckAssertions(conditions []string, lookup MetricLookup) error {
+	var failed []string
+	for _, cond := range conditions {
+		a, err := ParseAssertion(cond)
+		if err == nil {
+			err = a.Check(lookup)
+		}
+		if err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d assertions failed: %s", len(failed), len(conditions), strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// ParseAssertion parses the condition in the form "<metric>.<field> <op> <value>".
+// The value may be a duration (50ms) for the duration fields of DURATION and
+// HISTOGRAM metrics, a size in bytes (100MB), a number with a metric suffix
+// (1.5K) or a plain number for the other fields.
+func ParseAssertion(expr string) (Assertion, error) {
+	parts := assertionRegexp.FindStringSubmatch(expr)
+	if parts == nil {
+		return Assertion{}, fmt.Errorf("assertion %q must be in the form \"<metric>.<field> <op> <value>\": %w", expr, errors.ErrInvalid)
+	}
+	a := Assertion{Expr: strings.TrimSpace(expr), Op: parts[2]}
+	// metric names may contain dots as well as the fields (p99.9), so
+	// the rightmost split with a known field wins
+	left := parts[1]
+	for idx := strings.LastIndex(left, "."); idx > 0; idx = strings.LastIndex(left[:idx], ".") {
+		if metricFieldRegexp.MatchString(left[idx+1:]) {
+			a.Metric, a.Field = left[:idx], left[idx+1:]
+			break
+		}
+	}
+	if len(a.Metric) == 0 {
+		return Assertion{}, fmt.Errorf("assertion %q has no known metric field: %w", expr, errors.ErrInvalid)
+	}
+	a.Value = parts[3]
+	_, _, dErr := parseAssertionValue(a.Value, unitDuration)
+	_, _, sErr := parseAssertionValue(a.Value, unitNone)
+	if dErr != nil && sErr != nil {
+		return Assertion{}, fmt.Errorf("assertion %q has invalid value: %w", expr, sErr)
+	}
+	return a, nil
+}
+
+// fieldUnit returns the unit of the metric result field, the fields of
+// DURATION and HISTOGRAM metrics except total are durations
+func fieldUnit(result any, field string) valueUnit {
+	switch result.(type) {
+	case metrics.DurationMetricResult, metrics.HistogramMetricResult:
+		if field != "total" {
+			return unitDuration
+		}
+	}
+	return unitNone
+}
+
+// parseAssertionValue parses the value for the field of the unit, a duration
+// is expected for unitDuration and a size or a number for the others
+func parseAssertionValue(s string, unit valueUnit) (float64, valueUnit, error) {
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v, unit, nil
+	}
+	if unit == unitDuration {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, unit, fmt.Errorf("%q is not a duration: %w", s, errors.ErrInvalid)
+		}
+		return float64(d), unitDuration, nil
+	}
+	upper := strings.ToUpper(s)
+	for i, sfx := range bytesSuffixes {
+		if strings.HasSuffix(upper, sfx) {
+			if v, err := strconv.ParseFloat(upper[:len(upper)-len(sfx)], 64); err == nil {
+				for p := i; p < len(bytesSuffixes)-1; p++ {
+					v *= 1024
+				}
+				return v, unitBytes, nil
+			}
+		}
+	}
+	// the metric suffixes are upper case only, "5m" is not "5M"
+	for i, sfx := range sizeSuffixes {
+		if strings.HasSuffix(s, sfx) {
+			if v, err := strconv.ParseFloat(s[:len(s)-len(sfx)], 64); err == nil {
+				for p := i; p < len(sizeSuffixes); p++ {
+					v *= 1000
+				}
+				return v, unitNone, nil
+			}
+		}
+	}
+	return 0, unit, fmt.Errorf("%q is not a number or a size: %w", s, errors.ErrInvalid)
+}
+
+// Check looks the metric up and compares its field with the assertion value
+func (a Assertion) Check(lookup MetricLookup) error {
+	result, ok := lookup(a.Metric)
+	if !ok {
+		return fmt.Errorf("assertion %q failed: metric %q not found", a.Expr, a.Metric)
+	}
+	actual, err := metrics.ResultField(result, a.Field)
+	if err != nil {
+		return fmt.Errorf("assertion %q failed: %w", a.Expr, err)
+	}
+	value, unit, err := parseAssertionValue(a.Value, fieldUnit(result, a.Field))
+	if err != nil {
+		return fmt.Errorf("assertion %q does not match metric %q: %w", a.Expr, a.Metric, err)
+	}
+	var holds bool
+	switch a.Op {
+	case "<":
+		holds = actual < value
+	case "<=":
+		holds = actual <= value
+	case ">":
+		holds = actual > value
+	case ">=":
+		holds = actual >= value
+	case "==":
+		holds = actual == value
+	case "!=":
+		holds = actual != value
+	}
+	if !holds {
+		return fmt.Errorf("assertion %q failed: actual value is %s", a.Expr, formatValue(actual, unit))
+	}
+	return nil
+}
+
+func formatValue(v float64, unit valueUnit) string {
+	switch unit {
+	case unitDuration:
+		return time.Duration(int64(v)).String()
+	case unitBytes:
+		return utils.HumanReadableBytes(v)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// ContextMetricLookup returns the lookup of the metrics stored in the context,
+// the ErrorsMetricName pseudo metric counts the skipped errors (see SkippedErrorsNum).
+func ContextMetricLookup(ctx context.Context) MetricLookup {
+	return func(name string) (any, bool) {
+		if res, ok := GetMetricResult(ctx, name); ok {
+			return res, true
+		}
+		if name == ErrorsMetricName {
+			skipped := SkippedErrorsNum(ctx)
+			return metrics.IntMetricResult{Total: skipped, Sum: skipped}, true
+		}
+		return nil, false
+	}
+}
+
+// GetMetricResult returns the current result of the metric stored in the context
+func GetMetricResult(ctx context.Context, name string) (any, bool) {
+	if len(name) == 0 {
+		return nil, false
+	}
+	mv, _ := ctx.Value(name).(MetricValue)
+	return mv.Result()
+}
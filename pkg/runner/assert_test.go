@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAssertion(t *testing.T) {
+	a, err := ParseAssertion("AppendTimeout.mean < 50ms")
+	assert.NoError(t, err)
+	assert.Equal(t, "AppendTimeout", a.Metric)
+	assert.Equal(t, "mean", a.Field)
+	assert.Equal(t, "<", a.Op)
+	assert.Equal(t, "50ms", a.Value)
+
+	a, err = ParseAssertion("append.errors.total==0")
+	assert.NoError(t, err)
+	assert.Equal(t, "append.errors", a.Metric)
+	assert.Equal(t, "total", a.Field)
+	assert.Equal(t, "0", a.Value)
+
+	a, err = ParseAssertion("AppendLatency.p99.9 <= 1s")
+	assert.NoError(t, err)
+	assert.Equal(t, "AppendLatency", a.Metric)
+	assert.Equal(t, "p99.9", a.Field)
+
+	_, err = ParseAssertion("AppendBytesInSec.rate > 100MB")
+	assert.NoError(t, err)
+
+	a, err = ParseAssertion("AppendErrors.Unavailable < 5")
+	assert.NoError(t, err)
+	assert.Equal(t, "AppendErrors", a.Metric)
+	assert.Equal(t, "Unavailable", a.Field)
+	_, err = ParseAssertion("AppendErrors.Unavailible < 5")
+	assert.Error(t, err)
+
+	_, err = ParseAssertion("AppendTimeout < 50ms")
+	assert.Error(t, err)
+	_, err = ParseAssertion("AppendTimeout.mean ~ 50ms")
+	assert.Error(t, err)
+	_, err = ParseAssertion("AppendTimeout.mean < 50parsecs")
+	assert.Error(t, err)
+}
+
+func TestParseAssertionValue(t *testing.T) {
+	v, _, err := parseAssertionValue("5m", unitDuration)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5*time.Minute), v)
+	v, _, err = parseAssertionValue("5M", unitNone)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5000000), v)
+	v, unit, err := parseAssertionValue("100MB", unitNone)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(100*1024*1024), v)
+	assert.Equal(t, unitBytes, unit)
+	v, _, err = parseAssertionValue("1.5K", unitNone)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1500), v)
+
+	// the value must match the type of the metric field
+	_, _, err = parseAssertionValue("5m", unitNone)
+	assert.Error(t, err)
+	_, _, err = parseAssertionValue("5M", unitDuration)
+	assert.Error(t, err)
+}
+
+func TestCheckAssertions(t *testing.T) {
+	lookup := func(name string) (any, bool) {
+		switch name {
+		case "AppendTimeout":
+			return metrics.DurationMetricResult{Total: 2, Sum: 120 * time.Millisecond, Mean: 60 * time.Millisecond}, true
+		case "AppendErrors":
+			return metrics.CountersMetricResult{Total: 10, Counts: map[string]int64{"Unavailable": 7, "Internal": 3}}, true
+		case ErrorsMetricName:
+			return metrics.IntMetricResult{}, true
+		}
+		return nil, false
+	}
+	assert.NoError(t, CheckAssertions([]string{"AppendTimeout.mean < 100ms", "errors.total == 0"}, lookup))
+	err := CheckAssertions([]string{"AppendTimeout.mean < 50ms", "Unknown.total == 0", "errors.total == 0"}, lookup)
+	assert.ErrorContains(t, err, "2 of 3 assertions failed")
+	assert.ErrorContains(t, err, "actual value is 60ms")
+	assert.ErrorContains(t, err, "metric \"Unknown\" not found")
+
+	err = CheckAssertions([]string{"AppendTimeout.mean < 50M", "AppendTimeout.total < 5m"}, lookup)
+	assert.ErrorContains(t, err, "2 of 2 assertions failed")
+	assert.ErrorContains(t, err, "does not match metric")
+
+	// the counters are asserted by the gRPC status codes
+	assert.NoError(t, CheckAssertions([]string{"AppendErrors.Internal <= 3", "AppendErrors.Canceled == 0"}, lookup))
+	err = CheckAssertions([]string{"AppendErrors.Unavailable < 5", "AppendErrors.total < 20"}, lookup)
+	assert.ErrorContains(t, err, "1 of 2 assertions failed")
+	assert.ErrorContains(t, err, "actual value is 7")
+}
@@ -3,6 +3,7 @@ package runner
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/solarisdb/perftests/pkg/model"
 	"github.com/solarisdb/solaris/golibs/errors"
@@ -21,7 +22,10 @@ type (
 	}
 
 	AwaitCfg struct {
+		// TriggerName is the name of the trigger created by the trigger.create scenario
 		TriggerName string `yaml:"triggerName" json:"triggerName"`
+		// Timeout limits the awaiting, no limit if empty
+		Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 	}
 )
 
@@ -70,10 +74,40 @@ func (r *awaitRunner) run(ctx context.Context, config *model.ScenarioConfig) (do
 	}
 
 	awaitCtx, _ := ctx.Value(cfg.TriggerName).(context.Context)
+	if awaitCtx == nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("trigger %s not found: %w", cfg.TriggerName, errors.ErrNotExist)}
+		return
+	}
 	r.exec.Logger.Tracef("Start await %s", cfg.TriggerName)
-	<-awaitCtx.Done()
+	if err = awaitTrigger(ctx, cfg.TriggerName, awaitCtx, cfg.Timeout); err != nil {
+		doneCh <- &staticScenarioResult{ctx, err}
+		return
+	}
 	r.exec.Logger.Tracef("Complete await %s", cfg.TriggerName)
 
 	doneCh <- &staticScenarioResult{ctx: ctx, error: nil}
 	return
 }
+
+// awaitTrigger waits until the trigger is fired, the run context is closed
+// or the timeout (no limit if empty) is expired
+func awaitTrigger(ctx context.Context, name string, trigger context.Context, timeout string) error {
+	var timeoutCh <-chan time.Time
+	if len(timeout) > 0 {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return fmt.Errorf("failed to parse timeout %w", err)
+		}
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+	select {
+	case <-trigger.Done():
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("run context is closed %w", errors.ErrClosed)
+	case <-timeoutCh:
+		return fmt.Errorf("trigger %s is not fired in %s: %w", name, timeout, context.DeadlineExceeded)
+	}
+}
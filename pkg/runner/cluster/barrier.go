@@ -0,0 +1,229 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	cluster2 "github.com/solarisdb/perftests/pkg/cluster"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	barrier struct {
+		exec *barrierExecutor
+		name string
+	}
+
+	barrierExecutor struct {
+		name     string
+		Registry *runner.Registry `inject:""`
+		Logger   logging.Logger   `inject:""`
+
+		lock sync.Mutex
+		// generations counts the arrivals of the nodes by the node and
+		// the barrier name, so a barrier name passed again is awaited anew
+		generations map[string]int
+	}
+
+	// BarrierCfg describes the named barrier the cluster nodes wait on, so
+	// the scenario steps following the barrier start on all the nodes at
+	// about the same time. The barrier is scoped by the run ID of the
+	// cluster and by the generation, which is the number of times the node
+	// passed the barrier name before, so the barrier put into a loop is
+	// awaited on every iteration.
+	BarrierCfg struct {
+		Name string `yaml:"name" json:"name"`
+		// Nodes is the number of the nodes expected at the barrier, either
+		// Nodes or EnvNodes must be set
+		Nodes int `yaml:"nodes,omitempty" json:"nodes,omitempty"`
+		// EnvNodes is the env variable with the number of the nodes expected
+		// at the barrier, the Nodes is ignored if it is set
+		EnvNodes string `yaml:"envNodes,omitempty" json:"envNodes,omitempty"`
+		// Timeout limits awaiting of the other nodes, no limit if empty
+		Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+		// PollInterval is how often the arrived nodes are checked, 1s by default
+		PollInterval string `yaml:"pollInterval,omitempty" json:"pollInterval,omitempty"`
+	}
+)
+
+const (
+	BarrierName                = "cluster.barrier"
+	defaultBarrierPollInterval = time.Second
+)
+
+func NewBarrier(exec *barrierExecutor, prefix string) runner.ScenarioRunner {
+	return &barrier{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), runner.GetRunnerIndex())}
+}
+
+func NewBarrierExecutor() runner.ScenarioExecutor {
+	return &barrierExecutor{name: BarrierName, generations: make(map[string]int)}
+}
+
+func (r *barrierExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *barrierExecutor) Name() string {
+	return r.name
+}
+
+func (r *barrierExecutor) New(prefix string) runner.ScenarioRunner {
+	return NewBarrier(r, prefix)
+}
+
+func (r *barrier) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan runner.ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *barrier) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan runner.ScenarioResult) {
+	doneCh = make(chan runner.ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed))
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[BarrierCfg](config)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse scenario config %w", err))
+		return
+	}
+	if len(cfg.Name) == 0 {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("barrier name is empty: %w", errors.ErrInvalid))
+		return
+	}
+	nodes, err := nodesNumber(cfg.Nodes, cfg.EnvNodes)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, err)
+		return
+	}
+	if nodes <= 0 {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("the number of nodes expected at barrier %s is not set: %w", cfg.Name, errors.ErrInvalid))
+		return
+	}
+	pollInterval := defaultBarrierPollInterval
+	if len(cfg.PollInterval) > 0 {
+		if pollInterval, err = time.ParseDuration(cfg.PollInterval); err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse poll interval %w", err))
+			return
+		}
+	}
+	awaitCtx := ctx
+	if len(cfg.Timeout) > 0 {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse timeout %w", err))
+			return
+		}
+		var cancel context.CancelFunc
+		awaitCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	nodeClnt, _ := ctx.Value(clusterNode).(cluster2.Node)
+	if nodeClnt == nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("cluster node not found"))
+		return
+	}
+	cluster, _ := ctx.Value(clusterClnt).(cluster2.Cluster)
+	if cluster == nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("cluster not found"))
+		return
+	}
+	barrierID := r.exec.barrierID(cluster.ID(), nodeClnt.ID(), cfg.Name)
+	if err = nodeClnt.Arrive(awaitCtx, barrierID); err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, err)
+		return
+	}
+
+	start := time.Now()
+	var arrived, registered []string
+	for {
+		a, reg, err := barrierNodes(awaitCtx, cluster, nodeClnt.ID(), barrierID)
+		if err == nil {
+			arrived, registered = a, reg
+			if len(arrived) >= nodes {
+				r.exec.Logger.Infof("%s: %d nodes passed barrier %s in %s", r.name, len(arrived), barrierID, time.Since(start).Round(time.Millisecond))
+				break
+			}
+		} else if awaitCtx.Err() == nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, err)
+			return
+		}
+		select {
+		case <-awaitCtx.Done():
+		case <-time.After(pollInterval):
+			continue
+		}
+		if ctx.Err() != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed))
+			return
+		}
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("barrier %s is not passed in %s, %d of %d nodes arrived, missing nodes %v: %w",
+			barrierID, cfg.Timeout, len(arrived), nodes, missingNodes(arrived, registered), context.DeadlineExceeded))
+		return
+	}
+	doneCh <- runner.NewStaticScenarioResult(ctx, nil)
+	return
+}
+
+// barrierNodes returns the IDs of the nodes of the run of the node arrived
+// at the barrier and of all the nodes registered in the run, the nodes of
+// the former runs of the cluster are not counted
+func barrierNodes(ctx context.Context, cluster cluster2.Cluster, nodeID, barrier string) ([]string, []string, error) {
+	all, err := cluster.Arrived(ctx, barrier)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes, err := cluster.Generation(ctx, nodeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	registered := make([]string, len(nodes))
+	for i, n := range nodes {
+		registered[i] = n.ID()
+	}
+	var arrived []string
+	for _, id := range all {
+		if slices.Contains(registered, id) {
+			arrived = append(arrived, id)
+		}
+	}
+	return arrived, registered, nil
+}
+
+// barrierID returns the ID of the barrier the node arrives at, which is
+// the barrier name scoped by the run ID and by the generation of the node
+func (r *barrierExecutor) barrierID(runID, nodeID, name string) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	key := nodeID + "/" + name
+	gen := r.generations[key]
+	r.generations[key] = gen + 1
+	return fmt.Sprintf("%s/%s/%d", runID, name, gen)
+}
+
+// missingNodes returns the registered nodes not arrived at the barrier
+func missingNodes(arrived, registered []string) []string {
+	seen := make(map[string]bool, len(arrived))
+	for _, id := range arrived {
+		seen[id] = true
+	}
+	var res []string
+	for _, id := range registered {
+		if !seen[id] {
+			res = append(res, id)
+		}
+	}
+	return res
+}
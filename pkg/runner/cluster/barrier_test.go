@@ -0,0 +1,69 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solarisdb/perftests/pkg/client"
+	solarCluster "github.com/solarisdb/perftests/pkg/cluster/solaris"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBarrier(t *testing.T) {
+	ctx := context.Background()
+	conn, err := client.Dial(ctx, "inmem://test-barrier", nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+	cluster, err := solarCluster.NewCluster(ctx, "test-barrier", solaris.NewServiceClient(conn))
+	assert.NoError(t, err)
+	node1, err := cluster.AddNode(ctx)
+	assert.NoError(t, err)
+	node2, err := cluster.AddNode(ctx)
+	assert.NoError(t, err)
+
+	exec := NewBarrierExecutor().(*barrierExecutor)
+	exec.Logger = logging.NewLogger("test")
+	nodeCtx := func(node any) context.Context {
+		return context.WithValue(context.WithValue(ctx, clusterClnt, cluster), clusterNode, node)
+	}
+	runBarrier := func(node any, cfg BarrierCfg) <-chan runner.ScenarioResult {
+		return NewBarrier(exec, "test").RunScenario(nodeCtx(node), model.ToScenarioConfig(&cfg))
+	}
+
+	// the first node waits for the second one
+	resCh := make(chan runner.ScenarioResult, 1)
+	go func() {
+		resCh <- <-runBarrier(node1, BarrierCfg{Name: "start", Nodes: 2, PollInterval: "10ms", Timeout: "5s"})
+	}()
+	t.Setenv("TEST_BARRIER_NODES", "2")
+	res := <-runBarrier(node2, BarrierCfg{Name: "start", EnvNodes: "TEST_BARRIER_NODES", PollInterval: "10ms", Timeout: "5s"})
+	assert.NoError(t, res.Error())
+	assert.NoError(t, (<-resCh).Error())
+
+	// the barrier passed again does not pass on the former arrivals
+	res = <-runBarrier(node1, BarrierCfg{Name: "start", Nodes: 2, PollInterval: "10ms", Timeout: "50ms"})
+	assert.ErrorContains(t, res.Error(), node2.ID())
+	assert.ErrorIs(t, res.Error(), context.DeadlineExceeded)
+	res = <-runBarrier(node1, BarrierCfg{Name: "start", Nodes: 1, Timeout: "50ms"})
+	assert.NoError(t, res.Error())
+
+	// the number of the nodes is required
+	res = <-runBarrier(node1, BarrierCfg{Name: "stop"})
+	assert.ErrorIs(t, res.Error(), errors.ErrInvalid)
+
+	// the arrivals of the nodes of the former run are not counted
+	assert.NoError(t, node1.Finish(ctx, []byte("{}")))
+	node3, err := cluster.AddNode(ctx)
+	assert.NoError(t, err)
+	res = <-runBarrier(node3, BarrierCfg{Name: "start", Nodes: 2, PollInterval: "10ms", Timeout: "50ms"})
+	assert.ErrorIs(t, res.Error(), context.DeadlineExceeded)
+
+	nodes, err := cluster.Nodes(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 3)
+}
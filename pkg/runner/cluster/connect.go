@@ -2,13 +2,14 @@ package cluster
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"math/rand/v2"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/solarisdb/perftests/pkg/client"
 	cluster2 "github.com/solarisdb/perftests/pkg/cluster"
 	solarCluster "github.com/solarisdb/perftests/pkg/cluster/solaris"
 	"github.com/solarisdb/perftests/pkg/model"
@@ -16,9 +17,6 @@ import (
 	"github.com/solarisdb/solaris/api/gen/solaris/v1"
 	"github.com/solarisdb/solaris/golibs/errors"
 	"github.com/solarisdb/solaris/golibs/logging"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type (
@@ -34,14 +32,28 @@ type (
 	}
 
 	ConnectCfg struct {
-		Address       string `yaml:"address,omitempty" json:"address,omitempty"`
-		EnvVarAddress string `yaml:"envVarAddress,omitempty" json:"envVarAddress,omitempty"`
-		EnvRunID      string `yaml:"envRunID,omitempty" json:"envRunID,omitempty"`
+		Address       string          `yaml:"address,omitempty" json:"address,omitempty"`
+		EnvVarAddress string          `yaml:"envVarAddress,omitempty" json:"envVarAddress,omitempty"`
+		EnvRunID      string          `yaml:"envRunID,omitempty" json:"envRunID,omitempty"`
+		Grpc          *client.GrpcCfg `yaml:"grpc,omitempty" json:"grpc,omitempty"`
+		// Nodes is the number of the cluster nodes, it is the node count of
+		// the runner.NodeInfo the nodes share the workload by. Either Nodes
+		// or EnvNodes must be set, the nodes are added to the cluster at
+		// different times, so the registered nodes are not counted.
+		Nodes int `yaml:"nodes,omitempty" json:"nodes,omitempty"`
+		// EnvNodes is the env variable with the number of the cluster nodes,
+		// the Nodes is ignored if it is set
+		EnvNodes string `yaml:"envNodes,omitempty" json:"envNodes,omitempty"`
+		// NoNodeInfo connects the node without the runner.NodeInfo, so the
+		// number of the nodes is not required, e.g. to clean the cluster up
+		NoNodeInfo bool `yaml:"noNodeInfo,omitempty" json:"noNodeInfo,omitempty"`
 	}
 
 	connectScenarioResult struct {
-		cluster cluster2.Cluster
-		node    cluster2.Node
+		cluster  cluster2.Cluster
+		node     cluster2.Node
+		nodeInfo runner.NodeInfo
+		logger   logging.Logger
 	}
 )
 
@@ -93,12 +105,7 @@ func (r *connect) run(ctx context.Context, config *model.ScenarioConfig) (doneCh
 		return
 	}
 
-	address := cfg.Address
-	if len(cfg.EnvVarAddress) > 0 {
-		if res, ok := readEnvStr(cfg.EnvVarAddress); ok {
-			address = res
-		}
-	}
+	address := client.Address(cfg.Address, cfg.EnvVarAddress)
 
 	var runID string
 	if res, ok := readEnvStr(cfg.EnvRunID); ok {
@@ -108,7 +115,7 @@ func (r *connect) run(ctx context.Context, config *model.ScenarioConfig) (doneCh
 		return
 	}
 
-	conn, err := r.dial(address)
+	conn, err := client.Dial(ctx, address, cfg.Grpc)
 	if err != nil {
 		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to dial to address %s: %w", address, err))
 		return
@@ -125,23 +132,65 @@ func (r *connect) run(ctx context.Context, config *model.ScenarioConfig) (doneCh
 		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to add node to cluster %v: %w", cluster, err))
 		return
 	}
+	var nodeInfo runner.NodeInfo
+	if !cfg.NoNodeInfo {
+		if nodeInfo, err = getNodeInfo(ctx, cluster, node, cfg); err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to get node index in cluster %v: %w", cluster, err))
+			return
+		}
+		r.exec.Logger.Infof("%s: node %s is %d of %d nodes", r.name, node.ID(), nodeInfo.Index, nodeInfo.Count)
+	}
 	doneCh <- &connectScenarioResult{
-		cluster: cluster,
-		node:    node,
+		cluster:  cluster,
+		node:     node,
+		nodeInfo: nodeInfo,
+		logger:   r.exec.Logger,
 	}
 	return
 }
 
-func (r *connect) dial(addr string) (grpc.ClientConnInterface, error) {
-	initOpts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
+// getNodeInfo returns the index of the node, which is the order the node
+// is registered in the run of the cluster, and the configured number of
+// the cluster nodes
+func getNodeInfo(ctx context.Context, cluster cluster2.Cluster, node cluster2.Node, cfg ConnectCfg) (runner.NodeInfo, error) {
+	nodes, err := cluster.Generation(ctx, node.ID())
+	if err != nil {
+		return runner.NodeInfo{}, err
+	}
+	ni := runner.NodeInfo{ID: node.ID(), Index: -1}
+	for i, n := range nodes {
+		if n.ID() == node.ID() {
+			ni.Index = i
+			break
+		}
+	}
+	if ni.Index < 0 {
+		return ni, fmt.Errorf("node %s is not registered: %w", node.ID(), errors.ErrNotExist)
+	}
+	if ni.Count, err = nodesNumber(cfg.Nodes, cfg.EnvNodes); err != nil {
+		return ni, err
+	}
+	if ni.Count <= 0 {
+		return ni, fmt.Errorf("the number of nodes is not set: %w", errors.ErrInvalid)
 	}
-	if isTls(addr) {
-		// overwriting the TransportCredentials by TLS with default config
-		initOpts[0] = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))
+	if ni.Index >= ni.Count {
+		return ni, fmt.Errorf("node index %d exceeds the number of nodes %d: %w", ni.Index, ni.Count, errors.ErrExhausted)
 	}
-	return grpc.Dial(addr, initOpts...)
+	return ni, nil
+}
+
+// nodesNumber returns the number of the nodes read from the env variable
+// if it is set, or the nodes otherwise
+func nodesNumber(nodes int, envNodes string) (int, error) {
+	env, ok := readEnvStr(envNodes)
+	if !ok {
+		return nodes, nil
+	}
+	n, err := strconv.Atoi(env)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number of nodes %q in env var %s: %w", env, envNodes, errors.ErrInvalid)
+	}
+	return n, nil
 }
 
 func readEnvStr(envVar string) (string, bool) {
@@ -159,15 +208,6 @@ func readEnvStr(envVar string) (string, bool) {
 	return result, false
 }
 
-func isTls(addr string) bool {
-	idx := strings.LastIndex(addr, ":")
-	if idx == -1 {
-		return false // insecure
-	}
-	s1 := strings.Trim(addr[idx+1:], " ")
-	return s1 == "443"
-}
-
 func (r *connectScenarioResult) Ctx(ctx context.Context) context.Context {
 	client := ctx.Value(clusterClnt)
 	if client == nil {
@@ -176,6 +216,14 @@ func (r *connectScenarioResult) Ctx(ctx context.Context) context.Context {
 	node := ctx.Value(clusterNode)
 	if node == nil {
 		ctx = context.WithValue(ctx, clusterNode, r.node)
+		if r.nodeInfo.Count > 0 {
+			ctx = runner.WithNodeInfo(ctx, r.nodeInfo)
+		}
+		ctx = runner.WithMetricLabel(ctx, runner.RunLabel, r.cluster.ID())
+		ctx = runner.WithMetricLabel(ctx, runner.NodeLabel, r.node.ID())
+		runner.AddFinalizer(ctx, func(fCtx context.Context) {
+			finishInterrupted(fCtx, r.node, r.logger)
+		})
 	}
 	return ctx
 }
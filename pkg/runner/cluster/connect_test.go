@@ -0,0 +1,47 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solarisdb/perftests/pkg/client"
+	solarCluster "github.com/solarisdb/perftests/pkg/cluster/solaris"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNodeInfo(t *testing.T) {
+	ctx := context.Background()
+	conn, err := client.Dial(ctx, "inmem://test-node-info", nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+	cluster, err := solarCluster.NewCluster(ctx, "test-node-info", solaris.NewServiceClient(conn))
+	assert.NoError(t, err)
+	node1, err := cluster.AddNode(ctx)
+	assert.NoError(t, err)
+	node2, err := cluster.AddNode(ctx)
+	assert.NoError(t, err)
+
+	_, err = getNodeInfo(ctx, cluster, node1, ConnectCfg{})
+	assert.ErrorIs(t, err, errors.ErrInvalid)
+	ni, err := getNodeInfo(ctx, cluster, node1, ConnectCfg{Nodes: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, runner.NodeInfo{ID: node1.ID(), Index: 0, Count: 2}, ni)
+	ni, err = getNodeInfo(ctx, cluster, node2, ConnectCfg{Nodes: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, runner.NodeInfo{ID: node2.ID(), Index: 1, Count: 3}, ni)
+
+	// the nodes of the former run are not counted
+	assert.NoError(t, node1.Finish(ctx, []byte("{}")))
+	node3, err := cluster.AddNode(ctx)
+	assert.NoError(t, err)
+	ni, err = getNodeInfo(ctx, cluster, node3, ConnectCfg{Nodes: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, runner.NodeInfo{ID: node3.ID(), Index: 0, Count: 1}, ni)
+
+	t.Setenv("TEST_NODES", "1")
+	_, err = getNodeInfo(ctx, cluster, node2, ConnectCfg{Nodes: 3, EnvNodes: "TEST_NODES"})
+	assert.Error(t, err)
+}
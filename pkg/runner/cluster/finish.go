@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	cluster2 "github.com/solarisdb/perftests/pkg/cluster"
 	"github.com/solarisdb/perftests/pkg/metrics"
@@ -28,10 +29,16 @@ type (
 	FinishCfg struct {
 		Metrics map[runner.MetricsType][]string `yaml:"metrics,omitempty" json:"metrics,omitempty"`
 		Await   bool                            `yaml:"await,omitempty" json:"await,omitempty"`
+		// Thresholds contains the assertions over the merged metrics of all
+		// the cluster nodes, they are checked only if Await is true
+		Thresholds []string `yaml:"thresholds,omitempty" json:"thresholds,omitempty"`
+		// Timeout limits awaiting of the other nodes results, no limit if empty
+		Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 	}
 
 	nodeResult struct {
 		Status  string                       `json:"status" yaml:"status"`
+		Errors  int64                        `json:"errors,omitempty" yaml:"errors,omitempty"`
 		Metrics map[string]typedMetricResult `json:"metrics,omitempty" yaml:"metrics,omitempty"`
 	}
 
@@ -46,8 +53,9 @@ type (
 )
 
 const (
-	FinishName = "cluster.finish"
-	statusOK   = "OK"
+	FinishName        = "cluster.finish"
+	statusOK          = "OK"
+	statusInterrupted = "INTERRUPTED"
 )
 
 func NewFinish(exec *finishExecutor, prefix string) runner.ScenarioRunner {
@@ -100,54 +108,20 @@ func (r *finish) run(ctx context.Context, config *model.ScenarioConfig) (doneCh
 
 	var myResult nodeResult
 	myResult.Status = statusOK
+	myResult.Errors = runner.SkippedErrorsNum(ctx)
 	myResult.Metrics = make(map[string]typedMetricResult)
 	for mType, mNames := range cfg.Metrics {
 		for _, mName := range mNames {
-			switch mType {
-			case runner.DURATION:
-				if metric, ok := runner.GetDurationMetric(ctx, mName); ok {
-					metric = metric.Copy()
-					mResult := metrics.GetDurationMetricResult(metric)
-					var mr typedMetricResult
-					_ = mr.ToDuration(mResult)
-					myResult.Metrics[mName] = mr
-				} else {
-					r.exec.Logger.Warnf("Metric %s not found", mName)
-				}
-			case runner.RPS:
-				if metric, ok := runner.GetRateMetric(ctx, mName); ok {
-					metric = metric.Copy()
-					mResult := metrics.GetRateMetricResult(metric)
-					var mr typedMetricResult
-					_ = mr.ToRPS(mResult)
-					myResult.Metrics[mName] = mr
-				} else {
-					r.exec.Logger.Warnf("Metric %s not found", mName)
-				}
-			case runner.INT:
-				if metric, ok := runner.GetIntMetric(ctx, mName); ok {
-					metric = metric.Copy()
-					mResult := metrics.GetIntMetricResult(metric)
-					var mr typedMetricResult
-					_ = mr.ToInt(mResult)
-					myResult.Metrics[mName] = mr
-				} else {
-					r.exec.Logger.Warnf("Metric %s not found", mName)
-				}
-			case runner.STRING:
-				if metric, ok := runner.GetStringMetric(ctx, mName); ok {
-					metric = metric.Copy()
-					mResult := metrics.GetStringMetricResult(metric)
-					var mr typedMetricResult
-					_ = mr.ToString(mResult)
-					myResult.Metrics[mName] = mr
-				} else {
-					r.exec.Logger.Warnf("Metric %s not found", mName)
-				}
-			default:
-				doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("unknown metrics type: %s", mType))
+			mr, ok, err := getTypedMetricResult(ctx, mType, mName)
+			if err != nil {
+				doneCh <- runner.NewStaticScenarioResult(ctx, err)
 				return
 			}
+			if !ok {
+				r.exec.Logger.Warnf("Metric %s not found", mName)
+				continue
+			}
+			myResult.Metrics[mName] = mr
 		}
 	}
 	plResult, _ := json.Marshal(myResult)
@@ -160,27 +134,49 @@ func (r *finish) run(ctx context.Context, config *model.ScenarioConfig) (doneCh
 	}
 
 	if cfg.Await {
-		nodes, err := cluster.Nodes(ctx)
+		awaitCtx := ctx
+		if len(cfg.Timeout) > 0 {
+			timeout, err := time.ParseDuration(cfg.Timeout)
+			if err != nil {
+				doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse timeout %w", err))
+				return
+			}
+			var cancel context.CancelFunc
+			awaitCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		// the nodes of the former runs of the cluster are not awaited
+		nodes, err := cluster.Generation(awaitCtx, nodeClnt.ID())
 		if err != nil {
 			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to read cluster nodes %w", err))
 			return
 		}
-		results := make([]any, 0, len(nodes))
 		var passed int
+		// missing are the nodes which results are not read, failed are the
+		// nodes which results are not OK, they are failed too
+		var missing, failed []string
+		var errorsTotal int64
 		allMetrics := make(map[string]any)
 		r.exec.Logger.Debugf("// --------------------------------------------------")
 		for _, node := range nodes {
-			res, err := node.Result(ctx)
+			res, err := node.Result(awaitCtx)
 			if err != nil {
-				r.exec.Logger.Errorf("%v failed to read result", node)
+				r.exec.Logger.Errorf("%v failed to read result: %s", node, err.Error())
+				missing = append(missing, node.ID())
 				continue
 			}
 			var result nodeResult
-			results = append(results, result)
-			_ = json.Unmarshal(res, &result)
+			if err = json.Unmarshal(res, &result); err != nil {
+				r.exec.Logger.Errorf("%v invalid result: %s", node, err.Error())
+				missing = append(missing, node.ID())
+				continue
+			}
 			if result.Status == statusOK {
 				passed++
+			} else {
+				failed = append(failed, fmt.Sprintf("%s(%s)", node.ID(), result.Status))
 			}
+			errorsTotal += result.Errors
 			nodeMetrics := make(map[string]any)
 			for mName, tmr := range result.Metrics {
 				switch tmr.Type {
@@ -211,6 +207,33 @@ func (r *finish) run(ctx context.Context, config *model.ScenarioConfig) (doneCh
 					} else {
 						allMetrics[mName] = nodeM
 					}
+				case runner.HISTOGRAM:
+					nodeM, _ := tmr.Result.AsHistogram()
+					nodeMetrics[mName] = nodeM
+					if allM, ok := allMetrics[mName]; ok {
+						dAllM := allM.(metrics.HistogramMetricResult)
+						allMetrics[mName] = dAllM.Merge(nodeM)
+					} else {
+						allMetrics[mName] = nodeM
+					}
+				case runner.GAUGE:
+					nodeM, _ := tmr.Result.AsGauge()
+					nodeMetrics[mName] = nodeM
+					if allM, ok := allMetrics[mName]; ok {
+						dAllM := allM.(metrics.GaugeMetricResult)
+						allMetrics[mName] = dAllM.Merge(nodeM)
+					} else {
+						allMetrics[mName] = nodeM
+					}
+				case runner.COUNTERS:
+					nodeM, _ := tmr.Result.AsCounters()
+					nodeMetrics[mName] = nodeM
+					if allM, ok := allMetrics[mName]; ok {
+						dAllM := allM.(metrics.CountersMetricResult)
+						allMetrics[mName] = dAllM.Merge(nodeM)
+					} else {
+						allMetrics[mName] = nodeM
+					}
 				case runner.STRING:
 					nodeM, _ := tmr.Result.AsString()
 					nodeMetrics[mName] = nodeM
@@ -226,18 +249,106 @@ func (r *finish) run(ctx context.Context, config *model.ScenarioConfig) (doneCh
 			r.exec.Logger.Debugf("// %s status: %s, metrics: %v", node, result.Status, nodeMetrics)
 		}
 		r.exec.Logger.Infof("// --------------------------------------------------")
-		r.exec.Logger.Infof("// Total nodes: %d, Passed: %d, Failed: %d", len(nodes), passed, len(results)-passed)
+		r.exec.Logger.Infof("// Total nodes: %d, Passed: %d, Failed: %d", len(nodes), passed, len(nodes)-passed)
+		if len(missing) > 0 {
+			r.exec.Logger.Infof("// Missing results of nodes: %v", missing)
+		}
+		if len(failed) > 0 {
+			r.exec.Logger.Infof("// Failed nodes: %v", failed)
+		}
 		r.exec.Logger.Infof("// Total metrics:")
 		for mName, res := range allMetrics {
 			r.exec.Logger.Infof("//	- %s: %v", mName, res)
 		}
 		r.exec.Logger.Infof("// --------------------------------------------------")
+
+		// the metrics of the partial cluster are not checked against the thresholds
+		if len(missing) > 0 {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("results of %d of %d nodes are missing %v: %w",
+				len(missing), len(nodes), missing, errors.ErrNotExist))
+			return
+		}
+		err = runner.CheckAssertions(cfg.Thresholds, func(name string) (any, bool) {
+			if res, ok := allMetrics[name]; ok {
+				return res, true
+			}
+			if name == runner.ErrorsMetricName {
+				return metrics.IntMetricResult{Total: errorsTotal, Sum: errorsTotal}, true
+			}
+			return nil, false
+		})
+		if err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("cluster thresholds are breached: %w", err))
+			return
+		}
 	}
 
 	doneCh <- runner.NewStaticScenarioResult(ctx, nil)
 	return
 }
 
+func getTypedMetricResult(ctx context.Context, mType runner.MetricsType, mName string) (typedMetricResult, bool, error) {
+	var mr typedMetricResult
+	switch mType {
+	case runner.DURATION:
+		if metric, ok := runner.GetDurationMetric(ctx, mName); ok {
+			_ = mr.ToDuration(metrics.GetDurationMetricResult(metric.Copy()))
+			return mr, true, nil
+		}
+	case runner.RPS:
+		if metric, ok := runner.GetRateMetric(ctx, mName); ok {
+			_ = mr.ToRPS(metrics.GetRateMetricResult(metric.Copy()))
+			return mr, true, nil
+		}
+	case runner.INT:
+		if metric, ok := runner.GetIntMetric(ctx, mName); ok {
+			_ = mr.ToInt(metrics.GetIntMetricResult(metric.Copy()))
+			return mr, true, nil
+		}
+	case runner.HISTOGRAM:
+		if metric, ok := runner.GetHistogramMetric(ctx, mName); ok {
+			_ = mr.ToHistogram(metrics.GetHistogramMetricResult(metric))
+			return mr, true, nil
+		}
+	case runner.GAUGE:
+		if metric, ok := runner.GetGaugeMetric(ctx, mName); ok {
+			_ = mr.ToGauge(metrics.GetGaugeMetricResult(metric))
+			return mr, true, nil
+		}
+	case runner.COUNTERS:
+		if metric, ok := runner.GetCountersMetric(ctx, mName); ok {
+			_ = mr.ToCounters(metrics.GetCountersMetricResult(metric))
+			return mr, true, nil
+		}
+	case runner.STRING:
+		if metric, ok := runner.GetStringMetric(ctx, mName); ok {
+			_ = mr.ToString(metrics.GetStringMetricResult(metric.Copy()))
+			return mr, true, nil
+		}
+	default:
+		return mr, false, fmt.Errorf("unknown metrics type: %s", mType)
+	}
+	return mr, false, nil
+}
+
+// finishInterrupted writes the result of the interrupted node with all
+// the metrics created in the test, so the awaiting nodes are not stuck
+func finishInterrupted(ctx context.Context, node cluster2.Node, logger logging.Logger) {
+	var myResult nodeResult
+	myResult.Status = statusInterrupted
+	myResult.Errors = runner.SkippedErrorsNum(ctx)
+	myResult.Metrics = make(map[string]typedMetricResult)
+	for mName, mv := range runner.TrackedMetrics(ctx) {
+		if mr, ok, _ := getTypedMetricResult(ctx, mv.Type, mName); ok {
+			myResult.Metrics[mName] = mr
+		}
+	}
+	plResult, _ := json.Marshal(myResult)
+	if err := node.Finish(ctx, plResult); err != nil {
+		logger.Errorf("%v failed to write the interrupted result: %s", node, err.Error())
+	}
+}
+
 func (mr metricResult) MarshalJSON() ([]byte, error) {
 	b, err := mr.union.MarshalJSON()
 	return b, err
@@ -277,6 +388,33 @@ func (r *typedMetricResult) ToInt(v metrics.IntMetricResult) error {
 	r.Result = &result
 	return nil
 }
+func (r *typedMetricResult) ToHistogram(v metrics.HistogramMetricResult) error {
+	var result metricResult
+	if err := result.FromHistogram(v); err != nil {
+		return err
+	}
+	r.Type = runner.HISTOGRAM
+	r.Result = &result
+	return nil
+}
+func (r *typedMetricResult) ToGauge(v metrics.GaugeMetricResult) error {
+	var result metricResult
+	if err := result.FromGauge(v); err != nil {
+		return err
+	}
+	r.Type = runner.GAUGE
+	r.Result = &result
+	return nil
+}
+func (r *typedMetricResult) ToCounters(v metrics.CountersMetricResult) error {
+	var result metricResult
+	if err := result.FromCounters(v); err != nil {
+		return err
+	}
+	r.Type = runner.COUNTERS
+	r.Result = &result
+	return nil
+}
 func (r *typedMetricResult) ToString(v metrics.StringMetricResult) error {
 	var result metricResult
 	if err := result.FromString(v); err != nil {
@@ -301,6 +439,21 @@ func (mr *metricResult) FromInt(v metrics.IntMetricResult) error {
 	mr.union = b
 	return err
 }
+func (mr *metricResult) FromHistogram(v metrics.HistogramMetricResult) error {
+	b, err := json.Marshal(v)
+	mr.union = b
+	return err
+}
+func (mr *metricResult) FromGauge(v metrics.GaugeMetricResult) error {
+	b, err := json.Marshal(v)
+	mr.union = b
+	return err
+}
+func (mr *metricResult) FromCounters(v metrics.CountersMetricResult) error {
+	b, err := json.Marshal(v)
+	mr.union = b
+	return err
+}
 func (mr *metricResult) FromString(v metrics.StringMetricResult) error {
 	b, err := json.Marshal(v)
 	mr.union = b
@@ -321,6 +474,21 @@ func (mr metricResult) AsInt() (metrics.IntMetricResult, error) {
 	err := json.Unmarshal(mr.union, &body)
 	return body, err
 }
+func (mr metricResult) AsHistogram() (metrics.HistogramMetricResult, error) {
+	var body metrics.HistogramMetricResult
+	err := json.Unmarshal(mr.union, &body)
+	return body, err
+}
+func (mr metricResult) AsGauge() (metrics.GaugeMetricResult, error) {
+	var body metrics.GaugeMetricResult
+	err := json.Unmarshal(mr.union, &body)
+	return body, err
+}
+func (mr metricResult) AsCounters() (metrics.CountersMetricResult, error) {
+	var body metrics.CountersMetricResult
+	err := json.Unmarshal(mr.union, &body)
+	return body, err
+}
 func (mr metricResult) AsString() (metrics.StringMetricResult, error) {
 	var body metrics.StringMetricResult
 	err := json.Unmarshal(mr.union, &body)
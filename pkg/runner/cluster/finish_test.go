@@ -0,0 +1,41 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solarisdb/perftests/pkg/client"
+	solarCluster "github.com/solarisdb/perftests/pkg/cluster/solaris"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinish(t *testing.T) {
+	ctx := context.Background()
+	conn, err := client.Dial(ctx, "inmem://test-finish", nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+	cluster, err := solarCluster.NewCluster(ctx, "test-finish", solaris.NewServiceClient(conn))
+	assert.NoError(t, err)
+	node1, err := cluster.AddNode(ctx)
+	assert.NoError(t, err)
+	node2, err := cluster.AddNode(ctx)
+	assert.NoError(t, err)
+
+	exec := &finishExecutor{name: FinishName, Logger: logging.NewLogger("test")}
+	nodeCtx := context.WithValue(context.WithValue(ctx, clusterClnt, cluster), clusterNode, node1)
+	cfg := model.ToScenarioConfig(&FinishCfg{Await: true, Timeout: "50ms"})
+
+	// the node not finished in time fails the cluster run
+	res := <-NewFinish(exec, "test").RunScenario(nodeCtx, cfg)
+	assert.ErrorIs(t, res.Error(), errors.ErrNotExist)
+	assert.ErrorContains(t, res.Error(), node2.ID())
+
+	// the interrupted node is failed, but its result is read
+	finishInterrupted(ctx, node2, exec.Logger)
+	res = <-NewFinish(exec, "test").RunScenario(nodeCtx, cfg)
+	assert.NoError(t, res.Error())
+}
@@ -3,12 +3,10 @@ package runner
 import (
 	"context"
 	"fmt"
-	"math/rand"
-	"regexp"
-	"strconv"
 	"time"
 
 	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/utils"
 	"github.com/solarisdb/solaris/golibs/errors"
 	"github.com/solarisdb/solaris/golibs/logging"
 )
@@ -29,12 +27,6 @@ type (
 	}
 )
 
-var (
-	normalDelayRegexp   = regexp.MustCompile(`normal\s*\(\s*(\d+)\s*,\s*(\d+)\s*\)`)
-	uniformDelayRegexp  = regexp.MustCompile(`uniform\s*\(\s*(\d+)\s*,\s*(\d+)\s*\)`)
-	constantDelayRegexp = regexp.MustCompile(`constant\s*\(\s*(\d+)\s*\)`)
-)
-
 const DelayRunName = "delay"
 
 func NewDelayRunner(exec *delayExecutor, prefix string) ScenarioRunner {
@@ -83,50 +75,19 @@ func (r *delayRunner) run(ctx context.Context, config *model.ScenarioConfig) (do
 		return
 	}
 
-	time.Sleep(time.Millisecond * time.Duration(delay))
+	if err = Sleep(ctx, time.Millisecond*time.Duration(delay)); err != nil {
+		doneCh <- &staticScenarioResult{ctx, err}
+		return
+	}
 
 	doneCh <- &staticScenarioResult{ctx: ctx}
 	return
 }
 
 func (r *delayRunner) getDelay(delayFunc string) (int64, error) {
-	input := []byte(delayFunc)
-	if normalDelayRegexp.Match(input) {
-		params := normalDelayRegexp.FindSubmatch(input)
-
-		mean, err := strconv.Atoi(string(params[1]))
-		if err != nil {
-			return 0, err
-		}
-		stdDev, err := strconv.Atoi(string(params[2]))
-		if err != nil {
-			return 0, err
-		}
-
-		return int64(rand.NormFloat64()*float64(stdDev) + float64(mean)), nil
-	} else if uniformDelayRegexp.Match(input) {
-		params := uniformDelayRegexp.FindSubmatch(input)
-
-		min, err := strconv.Atoi(string(params[1]))
-		if err != nil {
-			return 0, err
-		}
-		max, err := strconv.Atoi(string(params[2]))
-		if err != nil {
-			return 0, err
-		}
-		if min > max {
-			return 0, fmt.Errorf("max should be greater than min: %w", errors.ErrInvalid)
-		}
-		return rand.Int63n(int64(max-min)) + int64(min), nil
-	} else if constantDelayRegexp.Match(input) {
-		params := constantDelayRegexp.FindSubmatch(input)
-		value, err := strconv.Atoi(string(params[1]))
-		if err != nil {
-			return 0, err
-		}
-		return int64(value), nil
+	dist, err := utils.ParseDistribution(delayFunc)
+	if err != nil {
+		return 0, err
 	}
-
-	return 0, fmt.Errorf("unknown delay function: %s: %w", delayFunc, errors.ErrInvalid)
+	return dist(), nil
 }
@@ -0,0 +1,290 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	// MetricsExporter exposes the metrics of the running tests in the
+	// Prometheus text format, it does nothing if the exporter is not configured
+	MetricsExporter struct {
+		Config *model.Config  `inject:""`
+		Logger logging.Logger `inject:""`
+
+		lock    sync.Mutex
+		enabled bool
+		metrics map[string]exportedMetric
+		srv     *http.Server
+	}
+
+	exportedMetric struct {
+		name   string
+		labels map[string]string
+		value  MetricValue
+	}
+)
+
+const (
+	// MetricLabels is the context key of the labels (map[string]string)
+	// added to the exported metrics
+	MetricLabels = "metricLabels"
+
+	TestLabel = "test"
+	RunLabel  = "run"
+	NodeLabel = "node"
+	// CountersKeyLabel is the label of the COUNTERS metric key
+	CountersKeyLabel = "key"
+
+	exporterMetricPrefix    = "perftests_"
+	defaultExporterPath     = "/metrics"
+	exporterShutdownTimeout = 5 * time.Second
+)
+
+var invalidMetricNameChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+func NewMetricsExporter() *MetricsExporter {
+	return &MetricsExporter{metrics: map[string]exportedMetric{}}
+}
+
+func (e *MetricsExporter) Init(ctx context.Context) error {
+	cfg := e.Config.Exporter
+	if cfg == nil || len(cfg.Address) == 0 {
+		return nil
+	}
+	path := cfg.Path
+	if len(path) == 0 {
+		path = defaultExporterPath
+	}
+	lis, err := net.Listen("tcp", cfg.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen metrics exporter address %s: %w", cfg.Address, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, e.serveHTTP)
+	e.srv = &http.Server{Handler: mux}
+	e.enabled = true
+	go func() {
+		if err := e.srv.Serve(lis); err != nil && err != http.ErrServerClosed {
+			e.Logger.Errorf("metrics exporter stopped: %s", err.Error())
+		}
+	}()
+	e.Logger.Infof("Metrics are exported on %s%s", lis.Addr().String(), path)
+	return nil
+}
+
+func (e *MetricsExporter) Shutdown() {
+	if e.srv == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), exporterShutdownTimeout)
+	defer cancel()
+	_ = e.srv.Shutdown(ctx)
+}
+
+// Register adds the metric to the exported ones, the metric is labeled with
+// the MetricLabels found in the context
+func (e *MetricsExporter) Register(ctx context.Context, name string, mv MetricValue) {
+	if e == nil || !e.enabled {
+		return
+	}
+	labels := GetMetricLabels(ctx)
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.metrics[exportedMetricKey(name, labels)] = exportedMetric{name: name, labels: labels, value: mv}
+}
+
+// Unregister removes all the metrics of the test from the exported ones
+func (e *MetricsExporter) Unregister(test string) {
+	if e == nil {
+		return
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for key, em := range e.metrics {
+		if em.labels[TestLabel] == test {
+			delete(e.metrics, key)
+		}
+	}
+}
+
+func (e *MetricsExporter) serveHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(e.export())
+}
+
+// export writes all the registered metrics in the Prometheus text format,
+// metrics of the same name and type are grouped under one TYPE line. INT
+// metrics are exported as summaries (_sum, _count), DURATION and HISTOGRAM as
+// summaries in seconds with the _seconds suffix, RPS and GAUGE as gauges,
+// STRING as counters of the added values and COUNTERS as counters with the
+// counter key in the "key" label. The metrics of the same name but of
+// different types are exported under the names suffixed by the type.
+func (e *MetricsExporter) export() []byte {
+	e.lock.Lock()
+	ems := make([]exportedMetric, 0, len(e.metrics))
+	for _, em := range e.metrics {
+		ems = append(ems, em)
+	}
+	e.lock.Unlock()
+	sort.Slice(ems, func(i, j int) bool {
+		return exportedMetricKey(ems[i].name, ems[i].labels) < exportedMetricKey(ems[j].name, ems[j].labels)
+	})
+
+	families := map[string]string{}
+	byName := map[string][]exportedMetric{}
+	for _, em := range ems {
+		name, typ := exportedFamily(em)
+		if t, ok := families[name]; ok && t != typ {
+			name += "_" + strings.ToLower(string(em.value.Type))
+		}
+		families[name] = typ
+		byName[name] = append(byName[name], em)
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, families[name])
+		for _, em := range byName[name] {
+			res, ok := em.value.Result()
+			if !ok {
+				continue
+			}
+			switch mr := res.(type) {
+			case metrics.IntMetricResult:
+				writeSample(&buf, name+"_sum", em.labels, float64(mr.Sum))
+				writeSample(&buf, name+"_count", em.labels, float64(mr.Total))
+			case metrics.DurationMetricResult:
+				writeSample(&buf, name+"_sum", em.labels, mr.Sum.Seconds())
+				writeSample(&buf, name+"_count", em.labels, float64(mr.Total))
+			case metrics.HistogramMetricResult:
+				for _, q := range metrics.HistogramPercentiles {
+					writeSample(&buf, name, withLabel(em.labels, "quantile", strconv.FormatFloat(q/100, 'f', -1, 64)), mr.Percentile(q).Seconds())
+				}
+				writeSample(&buf, name+"_sum", em.labels, time.Duration(mr.Sum).Seconds())
+				writeSample(&buf, name+"_count", em.labels, float64(mr.Total))
+			case metrics.RateMetricResult:
+				writeSample(&buf, name, em.labels, metrics.FromRateMetricResult(mr).Rate())
+			case metrics.GaugeMetricResult:
+				writeSample(&buf, name, em.labels, float64(mr.Value))
+			case metrics.CountersMetricResult:
+				keys := make([]string, 0, len(mr.Counts))
+				for k := range mr.Counts {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					writeSample(&buf, name, withLabel(em.labels, CountersKeyLabel, k), float64(mr.Counts[k]))
+				}
+			case metrics.StringMetricResult:
+				writeSample(&buf, name, em.labels, float64(mr.Total))
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// exportedFamily returns the name and the Prometheus type the metric is exported with
+func exportedFamily(em exportedMetric) (string, string) {
+	name := exporterMetricPrefix + invalidMetricNameChars.ReplaceAllString(em.name, "_")
+	switch em.value.Type {
+	case DURATION, HISTOGRAM:
+		return name + "_seconds", "summary"
+	case RPS, GAUGE:
+		return name, "gauge"
+	case COUNTERS, STRING:
+		return name + "_total", "counter"
+	}
+	return name, "summary"
+}
+
+func writeSample(buf *bytes.Buffer, name string, labels map[string]string, value float64) {
+	buf.WriteString(name)
+	if len(labels) > 0 {
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			fmt.Fprintf(buf, "%s=\"%s\"", k, escapeLabelValue(labels[k]))
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(formatSampleValue(value))
+	buf.WriteByte('\n')
+}
+
+func formatSampleValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func withLabel(labels map[string]string, key, value string) map[string]string {
+	res := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		res[k] = v
+	}
+	res[key] = value
+	return res
+}
+
+func exportedMetricKey(name string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, k := range keys {
+		sb.WriteString("|" + k + "=" + labels[k])
+	}
+	return sb.String()
+}
+
+// WithMetricLabel returns the context with the label added to the MetricLabels
+func WithMetricLabel(ctx context.Context, key, value string) context.Context {
+	return context.WithValue(ctx, MetricLabels, withLabel(GetMetricLabels(ctx), key, value))
+}
+
+// GetMetricLabels returns the MetricLabels stored in the context
+func GetMetricLabels(ctx context.Context) map[string]string {
+	labels, _ := ctx.Value(MetricLabels).(map[string]string)
+	return labels
+}
@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsExporter_Export(t *testing.T) {
+	e := NewMetricsExporter()
+	e.enabled = true
+
+	ctx := WithMetricLabel(context.Background(), TestLabel, "append \"1\"")
+	ctx = WithMetricLabel(ctx, NodeLabel, "n1")
+	timeout := metrics.NewScalar[int64]()
+	timeout.Add(int64(1500 * time.Millisecond))
+	e.Register(ctx, "AppendTimeout", MetricValue{Value: timeout, Type: DURATION})
+	latency := metrics.NewHistogram()
+	latency.Add(int64(time.Second))
+	e.Register(ctx, "Append.Latency", MetricValue{Value: latency, Type: HISTOGRAM})
+	errs := metrics.NewCounters()
+	errs.Add("Unavailable", 2)
+	e.Register(ctx, "AppendErrors", MetricValue{Value: errs, Type: COUNTERS})
+
+	out := string(e.export())
+	assert.Contains(t, out, "# TYPE perftests_AppendTimeout_seconds summary\n")
+	assert.Contains(t, out, "perftests_AppendTimeout_seconds_sum{node=\"n1\",test=\"append \\\"1\\\"\"} 1.5\n")
+	assert.Contains(t, out, "perftests_AppendTimeout_seconds_count{node=\"n1\",test=\"append \\\"1\\\"\"} 1\n")
+	assert.Contains(t, out, "perftests_Append_Latency_seconds{node=\"n1\",quantile=\"0.99\",test=\"append \\\"1\\\"\"} 1\n")
+	assert.Contains(t, out, "# TYPE perftests_AppendErrors_total counter\n")
+	assert.Contains(t, out, "perftests_AppendErrors_total{key=\"Unavailable\",node=\"n1\",test=\"append \\\"1\\\"\"} 2\n")
+
+	// the metrics of the same name but of different types are not grouped
+	ctx2 := WithMetricLabel(context.Background(), TestLabel, "other")
+	e.Register(ctx, "Writers", MetricValue{Value: metrics.NewScalar[int64](), Type: INT})
+	e.Register(ctx2, "Writers", MetricValue{Value: metrics.NewGauge(), Type: GAUGE})
+	out = string(e.export())
+	assert.Contains(t, out, "# TYPE perftests_Writers summary\n")
+	assert.Contains(t, out, "# TYPE perftests_Writers_gauge gauge\n")
+	assert.Contains(t, out, "perftests_Writers_gauge{test=\"other\"} 0\n")
+	assert.Equal(t, 1, strings.Count(out, "# TYPE perftests_Writers "))
+
+	e.Unregister("append \"1\"")
+	e.Unregister("other")
+	assert.Empty(t, e.export())
+}
@@ -0,0 +1,16 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPauseRegistry returns the registry with the pause executor registered,
+// the pause is the action of the most runner tests
+func newPauseRegistry(t *testing.T, logger logging.Logger) *Registry {
+	registry := NewRegistry()
+	assert.NoError(t, registry.Register(&pauseExecutor{name: PauseRunName, Registry: registry, Logger: logger}))
+	return registry
+}
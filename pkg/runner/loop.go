@@ -0,0 +1,190 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/container"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	loopRunner struct {
+		exec *loopExecutor
+		name string
+
+		lock    sync.Mutex
+		errs    map[int]error
+		errsNum int
+		failed  bool
+		index   int
+	}
+
+	loopExecutor struct {
+		name     string
+		Registry *Registry      `inject:""`
+		Logger   logging.Logger `inject:""`
+	}
+
+	// LoopCfg describes the action re-run by Workers concurrent workers (one
+	// by default) until Duration is elapsed, all the Until conditions hold or
+	// the run context is closed. The iteration in progress is finished when
+	// the duration is elapsed or the conditions hold, and it is interrupted
+	// when the context is closed. Every iteration starts with the context
+	// of the loop, the contexts of the iterations are not propagated.
+	LoopCfg struct {
+		Duration string `yaml:"duration,omitempty" json:"duration,omitempty"`
+		// Until contains the assertions (see AssertCfg) checked before
+		// every iteration, e.g. "AppendMsgsInSec.total >= 1000000"
+		Until      []string       `yaml:"until,omitempty" json:"until,omitempty"`
+		Workers    int            `yaml:"workers,omitempty" json:"workers,omitempty"`
+		Action     model.Scenario `yaml:"action" json:"action"`
+		SkipErrors bool           `yaml:"skipErrors,omitempty" json:"skipErrors,omitempty"`
+	}
+)
+
+const LoopRunName = "loop"
+
+func NewLoopRunner(exec *loopExecutor, prefix string) ScenarioRunner {
+	return &loopRunner{
+		exec: exec,
+		name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), GetRunnerIndex()),
+		errs: map[int]error{},
+	}
+}
+
+func NewLoopExecutor() ScenarioExecutor {
+	return &loopExecutor{name: LoopRunName}
+}
+
+func (r *loopExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *loopExecutor) Name() string {
+	return r.name
+}
+
+func (r *loopExecutor) New(prefix string) ScenarioRunner {
+	return NewLoopRunner(r, prefix)
+}
+
+func (r *loopRunner) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *loopRunner) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan ScenarioResult) {
+	doneCh = make(chan ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed)}
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[LoopCfg](config)
+	if err != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to parse scenario config %w", err)}
+		return
+	}
+	var deadline time.Time
+	if len(cfg.Duration) > 0 {
+		duration, err := time.ParseDuration(cfg.Duration)
+		if err != nil {
+			doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to parse duration %w", err)}
+			return
+		}
+		deadline = time.Now().Add(duration)
+	}
+	until := make([]Assertion, 0, len(cfg.Until))
+	for _, cond := range cfg.Until {
+		a, err := ParseAssertion(cond)
+		if err != nil {
+			doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to parse until condition: %w", err)}
+			return
+		}
+		until = append(until, a)
+	}
+	if deadline.IsZero() && len(until) == 0 {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("either duration or until must be set: %w", errors.ErrInvalid)}
+		return
+	}
+	executor, ok := r.exec.Registry.Get(cfg.Action.Name)
+	if !ok {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to get executor %s: %w", cfg.Action.Name, errors.ErrNotExist)}
+		return
+	}
+	workers := max(cfg.Workers, 1)
+
+	start := time.Now()
+	lookup := ContextMetricLookup(ctx)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wCtx := WithWorker(ctx)
+			for {
+				if ctx.Err() != nil || r.isFailed() {
+					return
+				}
+				if !deadline.IsZero() && time.Now().After(deadline) {
+					return
+				}
+				if len(until) > 0 && allHold(until, lookup) {
+					return
+				}
+				r.runIteration(wCtx, &cfg, executor)
+			}
+		}()
+	}
+	wg.Wait()
+	r.exec.Logger.Infof("%s: %d iterations done in %s", r.name, r.index, time.Since(start).Round(time.Millisecond))
+
+	r.lock.Lock()
+	errs, errsNum := container.CopyMap(r.errs), r.errsNum
+	r.lock.Unlock()
+	doneCh <- &rateScenarioResult{name: r.name, errs: errs, errsNum: errsNum, skipErrors: cfg.SkipErrors}
+	return
+}
+
+// runIteration runs the action once, the error is ignored if the action
+// is interrupted by closing the run context
+func (r *loopRunner) runIteration(ctx context.Context, cfg *LoopCfg, executor ScenarioExecutor) {
+	r.lock.Lock()
+	index := r.index
+	r.index++
+	r.lock.Unlock()
+	result := <-executor.New(r.name).RunScenario(ctx, ExpandConfig(ctx, cfg.Action.Config))
+	if err := result.Error(); err != nil && ctx.Err() == nil {
+		r.lock.Lock()
+		if len(r.errs) < maxRateErrors {
+			r.errs[index] = err
+		}
+		r.errsNum++
+		r.failed = !cfg.SkipErrors
+		r.lock.Unlock()
+	}
+}
+
+func (r *loopRunner) isFailed() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.failed
+}
+
+func allHold(assertions []Assertion, lookup MetricLookup) bool {
+	for _, a := range assertions {
+		if a.Check(lookup) != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoopRunner(t *testing.T) {
+	logger := logging.NewLogger("test")
+	registry := newPauseRegistry(t, logger)
+	loopExec := &loopExecutor{name: LoopRunName, Registry: registry, Logger: logger}
+	pause := model.Scenario{Name: PauseRunName, Config: model.ToScenarioConfig(&PauseCfg{Value: "10ms"})}
+
+	start := time.Now()
+	result := <-loopExec.New("").RunScenario(context.Background(), model.ToScenarioConfig(&LoopCfg{
+		Duration: "100ms",
+		Workers:  2,
+		Action:   pause,
+	}))
+	assert.NoError(t, result.Error())
+	assert.Less(t, time.Since(start), time.Second)
+
+	// the condition holds from the beginning, no action is run
+	counter := metrics.NewScalar[int64]()
+	ctx := context.WithValue(context.Background(), "counter", MetricValue{Value: counter, Type: INT})
+	actions := &counterExecutor{}
+	assert.NoError(t, registry.Register(actions))
+	result = <-loopExec.New("").RunScenario(ctx, model.ToScenarioConfig(&LoopCfg{
+		Until:  []string{"counter.total == 0"},
+		Action: model.Scenario{Name: actions.Name()},
+	}))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, int64(0), actions.runs.Load())
+
+	cctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	result = <-loopExec.New("").RunScenario(cctx, model.ToScenarioConfig(&LoopCfg{
+		Duration: "1h",
+		Action:   pause,
+	}))
+	assert.NoError(t, result.Error())
+	assert.Less(t, time.Since(start), time.Second)
+
+	// only the first skipped errors are kept, all of them are counted
+	assert.NoError(t, registry.Register(&errorExecutor{name: ErrorRunName, Logger: logger}))
+	result = <-loopExec.New("").RunScenario(context.Background(), model.ToScenarioConfig(&LoopCfg{
+		Duration:   "100ms",
+		Action:     model.Scenario{Name: ErrorRunName, Config: model.ToScenarioConfig(&ErrorCfg{Error: "test"})},
+		SkipErrors: true,
+	}))
+	assert.NoError(t, result.Error())
+	rr := result.(*rateScenarioResult)
+	assert.LessOrEqual(t, len(rr.errs), maxRateErrors)
+	assert.Greater(t, rr.errsNum, maxRateErrors)
+	skippedCtx := result.Ctx(context.WithValue(context.Background(), SkippedErrorsMap, map[string]error{}))
+	assert.Equal(t, int64(rr.errsNum), SkippedErrorsNum(skippedCtx))
+}
@@ -19,8 +19,9 @@ type (
 
 	metricsCreateExecutor struct {
 		name     string
-		Registry *Registry      `inject:""`
-		Logger   logging.Logger `inject:""`
+		Registry *Registry        `inject:""`
+		Exporter *MetricsExporter `inject:""`
+		Logger   logging.Logger   `inject:""`
 	}
 
 	metricsCreateScenarioResult struct {
@@ -45,6 +46,9 @@ const (
 	STRING               MetricsType = "STRING"
 	DURATION             MetricsType = "DURATION"
 	RPS                  MetricsType = "RPS"
+	HISTOGRAM            MetricsType = "HISTOGRAM"
+	GAUGE                MetricsType = "GAUGE"
+	COUNTERS             MetricsType = "COUNTERS"
 )
 
 func NewMetricsCreate(exec *metricsCreateExecutor, prefix string) ScenarioRunner {
@@ -98,11 +102,20 @@ func (r *metricsCreate) run(ctx context.Context, config *model.ScenarioConfig) (
 				toCreateMetrics[mName] = MetricValue{Value: metrics2.NewString(), Type: mType}
 			case RPS:
 				toCreateMetrics[mName] = MetricValue{Value: metrics2.NewRate(time.Second), Type: mType}
+			case HISTOGRAM:
+				toCreateMetrics[mName] = MetricValue{Value: metrics2.NewHistogram(), Type: mType}
+			case GAUGE:
+				toCreateMetrics[mName] = MetricValue{Value: metrics2.NewGauge(), Type: mType}
+			case COUNTERS:
+				toCreateMetrics[mName] = MetricValue{Value: metrics2.NewCounters(), Type: mType}
 			default:
 				doneCh <- NewStaticScenarioResult(ctx, fmt.Errorf("unknown metrics type: %s", mType))
 			}
 		}
 	}
+	for name, mv := range toCreateMetrics {
+		r.exec.Exporter.Register(ctx, name, mv)
+	}
 	doneCh <- &metricsCreateScenarioResult{metrics: toCreateMetrics}
 	return
 }
@@ -143,6 +156,42 @@ func GetRateMetric(ctx context.Context, name string) (*metrics2.Rate, bool) {
 	return nil, false
 }
 
+func GetHistogramMetric(ctx context.Context, name string) (*metrics2.Histogram, bool) {
+	var metric *metrics2.Histogram
+	if len(name) > 0 {
+		if mv, ok := ctx.Value(name).(MetricValue); ok && mv.Type == HISTOGRAM {
+			if metric, ok = mv.Value.(*metrics2.Histogram); ok {
+				return metric, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func GetGaugeMetric(ctx context.Context, name string) (*metrics2.Gauge, bool) {
+	var metric *metrics2.Gauge
+	if len(name) > 0 {
+		if mv, ok := ctx.Value(name).(MetricValue); ok && mv.Type == GAUGE {
+			if metric, ok = mv.Value.(*metrics2.Gauge); ok {
+				return metric, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func GetCountersMetric(ctx context.Context, name string) (*metrics2.Counters, bool) {
+	var metric *metrics2.Counters
+	if len(name) > 0 {
+		if mv, ok := ctx.Value(name).(MetricValue); ok && mv.Type == COUNTERS {
+			if metric, ok = mv.Value.(*metrics2.Counters); ok {
+				return metric, true
+			}
+		}
+	}
+	return nil, false
+}
+
 func GetStringMetric(ctx context.Context, name string) (*metrics2.String, bool) {
 	var metric *metrics2.String
 	if len(name) > 0 {
@@ -155,13 +204,56 @@ func GetStringMetric(ctx context.Context, name string) (*metrics2.String, bool)
 	return nil, false
 }
 
+// Result returns the metric result (IntMetricResult, DurationMetricResult etc.) of the metric value
+func (mv MetricValue) Result() (any, bool) {
+	switch mv.Type {
+	case INT:
+		if metric, ok := mv.Value.(*metrics2.Scalar[int64]); ok {
+			return metrics2.GetIntMetricResult(metric), true
+		}
+	case DURATION:
+		if metric, ok := mv.Value.(*metrics2.Scalar[int64]); ok {
+			return metrics2.GetDurationMetricResult(metric), true
+		}
+	case RPS:
+		if metric, ok := mv.Value.(*metrics2.Rate); ok {
+			return metrics2.GetRateMetricResult(metric.Copy()), true
+		}
+	case HISTOGRAM:
+		if metric, ok := mv.Value.(*metrics2.Histogram); ok {
+			return metrics2.GetHistogramMetricResult(metric), true
+		}
+	case GAUGE:
+		if metric, ok := mv.Value.(*metrics2.Gauge); ok {
+			return metrics2.GetGaugeMetricResult(metric), true
+		}
+	case COUNTERS:
+		if metric, ok := mv.Value.(*metrics2.Counters); ok {
+			return metrics2.GetCountersMetricResult(metric), true
+		}
+	case STRING:
+		if metric, ok := mv.Value.(*metrics2.String); ok {
+			return metrics2.GetStringMetricResult(metric), true
+		}
+	}
+	return nil, false
+}
+
 func (r *metricsCreateScenarioResult) Ctx(ctx context.Context) context.Context {
 	for name, val := range r.metrics {
-		ctx = context.WithValue(ctx, name, val)
+		ctx = WithMetric(ctx, name, val)
 	}
 	return ctx
 }
 
+// WithMetric returns the context with the metric added the same way the
+// metricsCreate runner adds them: the metric is tracked by the test
+func WithMetric(ctx context.Context, name string, mv MetricValue) context.Context {
+	ctx = context.WithValue(ctx, name, mv)
+	trackMetric(ctx, name, mv)
+	return ctx
+}
+
 func (r *metricsCreateScenarioResult) Error() error {
 	return nil
 }
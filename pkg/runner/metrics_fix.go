@@ -7,6 +7,7 @@ import (
 
 	"github.com/solarisdb/perftests/pkg/metrics"
 	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/container"
 	"github.com/solarisdb/solaris/golibs/errors"
 	"github.com/solarisdb/solaris/golibs/logging"
 )
@@ -34,6 +35,8 @@ type (
 
 const (
 	MetricsFixRunName = "metricsFix"
+	// FixedMetricsMap is the context key of the metrics fixed by metricsFix runners
+	FixedMetricsMap = "fixedMetrics"
 )
 
 func NewMetricsFix(exec *metricsFixExecutor, prefix string) ScenarioRunner {
@@ -103,6 +106,24 @@ func (r *metricsFix) run(ctx context.Context, config *model.ScenarioConfig) (don
 				r.exec.Logger.Infof("Metric %q: %s", mName, metric)
 				result[mName] = MetricValue{Value: metric, Type: mValue.Type}
 			}
+		case HISTOGRAM:
+			if metric, ok := GetHistogramMetric(ctx, mName); ok {
+				metric = metric.Copy()
+				r.exec.Logger.Infof("Metric %q: %s", mName, metric)
+				result[mName] = MetricValue{Value: metric, Type: mValue.Type}
+			}
+		case GAUGE:
+			if metric, ok := GetGaugeMetric(ctx, mName); ok {
+				metric = metric.Copy()
+				r.exec.Logger.Infof("Metric %q: %s", mName, metric)
+				result[mName] = MetricValue{Value: metric, Type: mValue.Type}
+			}
+		case COUNTERS:
+			if metric, ok := GetCountersMetric(ctx, mName); ok {
+				metric = metric.Copy()
+				r.exec.Logger.Infof("Metric %q: %s", mName, metric)
+				result[mName] = MetricValue{Value: metric, Type: mValue.Type}
+			}
 		case STRING:
 			if metric, ok := GetStringMetric(ctx, mName); ok {
 				metric = metric.Copy()
@@ -119,10 +140,15 @@ func (r *metricsFix) run(ctx context.Context, config *model.ScenarioConfig) (don
 }
 
 func (r *metricsFixScenarioResult) Ctx(ctx context.Context) context.Context {
+	fixed := map[string]MetricValue{}
+	if prev, ok := ctx.Value(FixedMetricsMap).(map[string]MetricValue); ok {
+		fixed = container.CopyMap(prev)
+	}
 	for name, val := range r.metrics {
 		ctx = context.WithValue(ctx, name, val)
+		fixed[name] = val
 	}
-	return ctx
+	return context.WithValue(ctx, FixedMetricsMap, fixed)
 }
 
 func (r *metricsFixScenarioResult) Error() error {
@@ -0,0 +1,148 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/solarisdb/perftests/pkg/model"
+)
+
+type (
+	// NodeInfo is the position of the node in the cluster run, the nodes
+	// share the workload by referencing it in the scenario configs
+	NodeInfo struct {
+		// ID is the unique ID of the node, it scopes the node data, e.g. the log tags
+		ID string
+		// Index is the stable index of the node, from 0 to Count-1
+		Index int
+		// Count is the number of the cluster nodes
+		Count int
+	}
+)
+
+// NodeInfoKey is the context key of the NodeInfo
+const NodeInfoKey = "nodeInfo"
+
+// placeholderRegexp matches the node placeholders, e.g. "${nodeIndex}" or "${share(100)}"
+var placeholderRegexp = regexp.MustCompile(`\$\{\s*(\w+)\s*(?:\(\s*(\d+)\s*\))?\s*}`)
+
+// valueRegexp matches the string consisting of a single node placeholder
+var valueRegexp = regexp.MustCompile(`^` + placeholderRegexp.String() + `$`)
+
+// WithNodeInfo returns the context with the node info
+func WithNodeInfo(ctx context.Context, ni NodeInfo) context.Context {
+	return context.WithValue(ctx, NodeInfoKey, ni)
+}
+
+// GetNodeInfo returns the node info stored in the context
+func GetNodeInfo(ctx context.Context) (NodeInfo, bool) {
+	ni, ok := ctx.Value(NodeInfoKey).(NodeInfo)
+	return ni, ok
+}
+
+// Share returns the number of the n items (e.g. logs) the node handles,
+// the items are split between the nodes as evenly as possible
+func (ni NodeInfo) Share(n int) int {
+	count := max(ni.Count, 1)
+	res := n / count
+	if ni.Index < n%count {
+		res++
+	}
+	return res
+}
+
+// ShardFirst returns the index of the first of the n items the node handles
+func (ni NodeInfo) ShardFirst(n int) int {
+	count := max(ni.Count, 1)
+	return ni.Index*(n/count) + min(ni.Index, n%count)
+}
+
+// value returns the value of the placeholder, which is either the string
+// or the int, false if the placeholder is unknown
+func (ni NodeInfo) value(name, arg string) (any, bool) {
+	n, err := strconv.Atoi(arg)
+	hasArg := len(arg) > 0 && err == nil
+	switch {
+	case name == "nodeID" && !hasArg:
+		return ni.ID, true
+	case name == "nodeIndex" && !hasArg:
+		return ni.Index, true
+	case name == "nodeCount" && !hasArg:
+		return ni.Count, true
+	case name == "share" && hasArg:
+		return ni.Share(n), true
+	case name == "shardFirst" && hasArg:
+		return ni.ShardFirst(n), true
+	case name == "shardLast" && hasArg:
+		return ni.ShardFirst(n) + ni.Share(n) - 1, true
+	}
+	return nil, false
+}
+
+// ExpandConfig returns the config with the node placeholders replaced by
+// their values when the node info is in the context. The placeholders are
+// "${nodeID}", "${nodeIndex}", "${nodeCount}", "${share(n)}" (the number of the n items
+// of the node), "${shardFirst(n)}" and "${shardLast(n)}" (the first and the
+// last index of the node items). The string value consisting of a single
+// numeric placeholder is replaced by the number, so "${share(100)}" may be
+// the count, the placeholders within the longer strings are replaced by
+// their text, e.g. "log-${nodeIndex}". The unknown placeholders are left as is.
+func ExpandConfig(ctx context.Context, config *model.ScenarioConfig) *model.ScenarioConfig {
+	ni, ok := GetNodeInfo(ctx)
+	if !ok || config == nil || !placeholderRegexp.Match(config.RawCfg) {
+		return config
+	}
+	dec := json.NewDecoder(bytes.NewReader(config.RawCfg))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return config
+	}
+	raw, err := json.Marshal(ni.expand(v))
+	if err != nil {
+		return config
+	}
+	return &model.ScenarioConfig{RawCfg: raw}
+}
+
+// expand returns the JSON value with the node placeholders replaced
+func (ni NodeInfo) expand(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for k, e := range v {
+			res[ni.expandString(k)] = ni.expand(e)
+		}
+		return res
+	case []any:
+		for i, e := range v {
+			v[i] = ni.expand(e)
+		}
+		return v
+	case string:
+		if sm := valueRegexp.FindStringSubmatch(v); sm != nil {
+			if n, ok := ni.value(sm[1], sm[2]); ok {
+				if i, ok := n.(int); ok {
+					return json.Number(strconv.Itoa(i))
+				}
+			}
+		}
+		return ni.expandString(v)
+	}
+	return v
+}
+
+// expandString returns the string with the node placeholders replaced by their text
+func (ni NodeInfo) expandString(s string) string {
+	return placeholderRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		sm := placeholderRegexp.FindStringSubmatch(m)
+		if v, ok := ni.value(sm[1], sm[2]); ok {
+			return fmt.Sprint(v)
+		}
+		return m
+	})
+}
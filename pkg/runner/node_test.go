@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeInfo_Share(t *testing.T) {
+	var total int
+	next := 0
+	for i := 0; i < 3; i++ {
+		ni := NodeInfo{Index: i, Count: 3}
+		assert.Equal(t, next, ni.ShardFirst(100))
+		next += ni.Share(100)
+		total += ni.Share(100)
+	}
+	assert.Equal(t, 100, total)
+	assert.Equal(t, 34, NodeInfo{Index: 0, Count: 3}.Share(100))
+	assert.Equal(t, 33, NodeInfo{Index: 2, Count: 3}.Share(100))
+	assert.Equal(t, 0, NodeInfo{Index: 2, Count: 3}.Share(2))
+}
+
+func TestExpandConfig(t *testing.T) {
+	type cfg struct {
+		Count int               `json:"count"`
+		Steps []int             `json:"steps"`
+		Tags  map[string]string `json:"tags"`
+		Value string            `json:"value"`
+	}
+	config := model.ToScenarioConfig(map[string]any{
+		"count": "${share(10)}",
+		"steps": []string{"${ shardFirst(10) }", "${shardLast(10)}"},
+		"tags":  map[string]string{"node": "node-${nodeIndex}", "id": "${nodeID}", "range": "{{int(${shardFirst(10)}, ${shardLast(10)})}}", "other": "${unknown}"},
+		"value": "5",
+	})
+	assert.Equal(t, config, ExpandConfig(context.Background(), config))
+
+	ctx := WithNodeInfo(context.Background(), NodeInfo{ID: "n1", Index: 1, Count: 4})
+	res, err := model.FromScenarioConfig[cfg](ExpandConfig(ctx, config))
+	assert.NoError(t, err)
+	assert.Equal(t, cfg{
+		Count: 3,
+		Steps: []int{3, 5},
+		Tags:  map[string]string{"node": "node-1", "id": "n1", "range": "{{int(3, 5)}}", "other": "${unknown}"},
+		Value: "5",
+	}, res)
+
+	// the single numeric placeholder is the number, not the string
+	_, err = model.FromScenarioConfig[cfg](ExpandConfig(ctx, model.ToScenarioConfig(map[string]any{"value": "${nodeIndex}"})))
+	assert.Error(t, err)
+}
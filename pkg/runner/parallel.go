@@ -6,6 +6,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/solarisdb/perftests/pkg/metrics"
 	"github.com/solarisdb/perftests/pkg/model"
 	"github.com/solarisdb/solaris/golibs/container"
 	context2 "github.com/solarisdb/solaris/golibs/context"
@@ -26,6 +27,10 @@ type (
 		doneCh    chan struct{}
 		doneCtx   context.Context
 		stepCount int32
+		inFlight  *metrics.Gauge
+		// lastAction is the index of the kept result of the succeeded
+		// action, the step indexes start from 1
+		lastAction int
 	}
 	parallelExecutor struct {
 		name     string
@@ -35,6 +40,20 @@ type (
 	ParallelCfg struct {
 		SkipErrors bool             `yaml:"skipErrors,omitempty" json:"skipErrors,omitempty"`
 		Steps      []model.Scenario `yaml:"steps" json:"steps"`
+		// Action is run Count times after the Steps, the actions are not
+		// listed in the Steps but generated one by one when started. If the
+		// MaxConcurrency is set, only the failed actions and the last
+		// succeeded one keep their results, so the context of the last
+		// succeeded action is propagated. The results of all the actions are
+		// kept and their contexts are merged otherwise.
+		Action *model.Scenario `yaml:"action,omitempty" json:"action,omitempty"`
+		Count  int             `yaml:"count,omitempty" json:"count,omitempty"`
+		// MaxConcurrency is the max number of the steps run at a time, the
+		// steps are run by the pool of MaxConcurrency workers then. All the
+		// steps are started at once if 0.
+		MaxConcurrency int `yaml:"maxConcurrency,omitempty" json:"maxConcurrency,omitempty"`
+		// InFlightMetricName is the GAUGE metric of the running steps
+		InFlightMetricName string `yaml:"inFlightMetricName,omitempty" json:"inFlightMetricName,omitempty"`
 	}
 
 	parallelScenarioResult struct {
@@ -120,15 +139,49 @@ func (r *ParallelRunner) run(ctx context.Context, config *model.ScenarioConfig)
 	}()
 	defer r.wg.Done()
 
-	for _, step := range cfg.Steps {
-		inStep := step
-		indx := int(atomic.AddInt32(&r.stepCount, 1))
-		r.startStep(r.runCtx, indx, &inStep, false)
+	r.inFlight, _ = GetGaugeMetric(ctx, cfg.InFlightMetricName)
+	count := len(cfg.Steps)
+	if cfg.Action != nil {
+		count += cfg.Count
+	}
+	if cfg.MaxConcurrency <= 0 {
+		for i := 0; i < count; i++ {
+			step, _ := cfg.step(i)
+			indx := int(atomic.AddInt32(&r.stepCount, 1))
+			r.startStep(r.runCtx, indx, step, false, false)
+		}
+		return
+	}
+	var next atomic.Int64
+	for w := 0; w < min(cfg.MaxConcurrency, count); w++ {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			for i := int(next.Add(1) - 1); i < count; i = int(next.Add(1) - 1) {
+				indx := int(atomic.AddInt32(&r.stepCount, 1))
+				if r.runCtx.Err() != nil {
+					// the rest of the steps are not run
+					r.setResult(indx, &staticScenarioResult{r.runCtx, fmt.Errorf("run context is closed %w", errors.ErrClosed)}, false)
+					next.Store(int64(count))
+					return
+				}
+				step, isAction := cfg.step(i)
+				r.runStep(r.runCtx, indx, step, isAction)
+			}
+		}()
 	}
 	return
 }
 
-func (r *ParallelRunner) startStep(runCtx context.Context, index int, pStep *model.Scenario, await bool) <-chan ScenarioResult {
+// step returns the i-th step of the config and whether it is the Action
+func (cfg *ParallelCfg) step(i int) (*model.Scenario, bool) {
+	if i < len(cfg.Steps) {
+		return &cfg.Steps[i], false
+	}
+	return cfg.Action, true
+}
+
+func (r *ParallelRunner) startStep(runCtx context.Context, index int, pStep *model.Scenario, isAction, await bool) <-chan ScenarioResult {
 	r.wg.Add(1)
 	var doneCh chan ScenarioResult
 	if await {
@@ -145,22 +198,39 @@ func (r *ParallelRunner) startStep(runCtx context.Context, index int, pStep *mod
 			}()
 		}
 		defer r.wg.Done()
-		stepRunner, ok := r.exec.Registry.Get(pStep.Name)
-		if !ok {
-			r.lock.Lock()
-			r.stepRslts[index] = &staticScenarioResult{runCtx, fmt.Errorf("failed to get runner for step %s: %w", pStep.Name, errors.ErrNotExist)}
-			r.lock.Unlock()
-			return
-		}
-
-		result := <-stepRunner.New(r.name).RunScenario(runCtx, pStep.Config)
-		r.lock.Lock()
-		r.stepRslts[index] = result
-		r.lock.Unlock()
+		r.runStep(runCtx, index, pStep, isAction)
 	}()
 	return doneCh
 }
 
+// runStep runs the step and keeps its result
+func (r *ParallelRunner) runStep(runCtx context.Context, index int, pStep *model.Scenario, isAction bool) {
+	stepRunner, ok := r.exec.Registry.Get(pStep.Name)
+	if !ok {
+		r.setResult(index, &staticScenarioResult{runCtx, fmt.Errorf("failed to get runner for step %s: %w", pStep.Name, errors.ErrNotExist)}, false)
+		return
+	}
+	if r.inFlight != nil {
+		r.inFlight.Add(1)
+		defer r.inFlight.Add(-1)
+	}
+	runCtx = WithWorker(runCtx)
+	result := <-stepRunner.New(r.name).RunScenario(runCtx, ExpandConfig(runCtx, pStep.Config))
+	r.setResult(index, result, isAction)
+}
+
+// setResult keeps the step result, only the last succeeded action result
+// is kept of the actions run by the pool
+func (r *ParallelRunner) setResult(index int, result ScenarioResult, isAction bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if isAction && result.Error() == nil {
+		delete(r.stepRslts, r.lastAction)
+		r.lastAction = index
+	}
+	r.stepRslts[index] = result
+}
+
 func (r *ParallelRunner) addStep() (int, bool) {
 	if r.doneCtx.Err() == nil {
 		indx := int(atomic.AddInt32(&r.stepCount, 1))
@@ -171,7 +241,7 @@ func (r *ParallelRunner) addStep() (int, bool) {
 
 func (r *ParallelRunner) AddScenario(runCtx context.Context, step model.Scenario) (bool, <-chan ScenarioResult) {
 	if indx, ok := r.addStep(); ok {
-		doneCh := r.startStep(runCtx, indx, &step, true)
+		doneCh := r.startStep(runCtx, indx, &step, false, true)
 		return true, doneCh
 	}
 	return false, nil
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelMaxConcurrency(t *testing.T) {
+	registry := NewRegistry()
+	logger := logging.NewLogger("test")
+	assert.NoError(t, registry.Register(&sequenceExecutor{Registry: registry, Logger: logger}))
+	assert.NoError(t, registry.Register(&parallelExecutor{name: ParallelRunName, Registry: registry, Logger: logger}))
+	assert.NoError(t, registry.Register(&repeatExecutor{name: RepeatRunName, Registry: registry, Logger: logger}))
+	counter := &counterExecutor{}
+	assert.NoError(t, registry.Register(counter))
+
+	inFlight := metrics.NewGauge()
+	ctx := context.WithValue(context.Background(), "inFlight", MetricValue{Value: inFlight, Type: GAUGE})
+	executor, _ := registry.Get(RepeatRunName)
+	res := <-executor.New("test").RunScenario(ctx, model.ToScenarioConfig(&RepeatCfg{
+		Count:              20,
+		Executor:           ParallelRunName,
+		Action:             model.Scenario{Name: "counter"},
+		MaxConcurrency:     3,
+		InFlightMetricName: "inFlight",
+	}))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, int64(20), counter.runs.Load())
+	assert.LessOrEqual(t, counter.maxActive.Load(), int64(3))
+	assert.LessOrEqual(t, inFlight.Max(), int64(3))
+	assert.Equal(t, int64(0), inFlight.Value())
+
+	// the failed actions are reported
+	counter.runs.Store(0)
+	executor, _ = registry.Get(ParallelRunName)
+	res = <-executor.New("test").RunScenario(ctx, model.ToScenarioConfig(&ParallelCfg{
+		Steps:          []model.Scenario{{Name: "unknown"}},
+		Action:         &model.Scenario{Name: "counter"},
+		Count:          5,
+		MaxConcurrency: 2,
+	}))
+	assert.Error(t, res.Error())
+	assert.Equal(t, int64(5), counter.runs.Load())
+	assert.Len(t, res.(*parallelScenarioResult).results, 2)
+
+	// the results of all the actions are kept without the pool
+	res = <-executor.New("test").RunScenario(ctx, model.ToScenarioConfig(&ParallelCfg{
+		Action: &model.Scenario{Name: "counter"},
+		Count:  5,
+	}))
+	assert.NoError(t, res.Error())
+	assert.Len(t, res.(*parallelScenarioResult).results, 5)
+}
+
+type counterExecutor struct {
+	active    atomic.Int64
+	maxActive atomic.Int64
+	runs      atomic.Int64
+}
+
+func (c *counterExecutor) Name() string {
+	return "counter"
+}
+
+func (c *counterExecutor) New(prefix string) ScenarioRunner {
+	return c
+}
+
+func (c *counterExecutor) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan ScenarioResult {
+	active := c.active.Add(1)
+	for m := c.maxActive.Load(); active > m && !c.maxActive.CompareAndSwap(m, active); m = c.maxActive.Load() {
+	}
+	time.Sleep(5 * time.Millisecond)
+	c.active.Add(-1)
+	c.runs.Add(1)
+	doneCh := make(chan ScenarioResult, 1)
+	doneCh <- NewStaticScenarioResult(ctx, nil)
+	close(doneCh)
+	return doneCh
+}
@@ -76,7 +76,10 @@ func (r *pauseRunner) run(ctx context.Context, config *model.ScenarioConfig) (do
 			doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to parse pause value %w", err)}
 			return
 		}
-		time.Sleep(pVal)
+		if err = Sleep(ctx, pVal); err != nil {
+			doneCh <- &staticScenarioResult{ctx, err}
+			return
+		}
 	}
 	doneCh <- &pauseScenarioResult{ctx: ctx, error: nil}
 	return
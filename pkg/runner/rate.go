@@ -0,0 +1,245 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/container"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	rateRunner struct {
+		exec *rateExecutor
+		name string
+
+		lock    sync.Mutex
+		wg      sync.WaitGroup
+		errs    map[int]error
+		errsNum int
+		failed  bool
+	}
+
+	rateExecutor struct {
+		name     string
+		Registry *Registry      `inject:""`
+		Logger   logging.Logger `inject:""`
+	}
+
+	// RateCfg describes the open-model load: the action is started Rate times
+	// per second regardless of how long the previous actions take, until
+	// Duration is elapsed or Count actions are started.
+	RateCfg struct {
+		Rate     float64        `yaml:"rate" json:"rate"`
+		Duration string         `yaml:"duration,omitempty" json:"duration,omitempty"`
+		Count    int            `yaml:"count,omitempty" json:"count,omitempty"`
+		Action   model.Scenario `yaml:"action" json:"action"`
+		// MaxInFlight is the max number of the running actions, the start is
+		// dropped if the limit is reached. Zero means no limit.
+		MaxInFlight int  `yaml:"maxInFlight,omitempty" json:"maxInFlight,omitempty"`
+		SkipErrors  bool `yaml:"skipErrors,omitempty" json:"skipErrors,omitempty"`
+		// DroppedMetric is the INT metric counting the starts dropped because
+		// of the MaxInFlight limit
+		DroppedMetric string `yaml:"droppedMetric,omitempty" json:"droppedMetric,omitempty"`
+		// LateMetric is the INT metric counting the starts which happened
+		// later than the scheduled time by more than one interval
+		LateMetric string `yaml:"lateMetric,omitempty" json:"lateMetric,omitempty"`
+	}
+
+	// rateScenarioResult is the result of the runners starting the same action
+	// many times, it keeps the errors of the first failed actions only and
+	// the number of all the failed actions
+	rateScenarioResult struct {
+		name       string
+		errs       map[int]error
+		errsNum    int
+		skipErrors bool
+	}
+)
+
+const (
+	RateRunName = "rate"
+	// maxRateErrors is the max number of the action errors kept by the
+	// runner, the long failing runs would grow the memory otherwise
+	maxRateErrors = 100
+)
+
+func NewRateRunner(exec *rateExecutor, prefix string) ScenarioRunner {
+	return &rateRunner{
+		exec: exec,
+		name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), GetRunnerIndex()),
+		errs: map[int]error{},
+	}
+}
+
+func NewRateExecutor() ScenarioExecutor {
+	return &rateExecutor{name: RateRunName}
+}
+
+func (r *rateExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *rateExecutor) Name() string {
+	return r.name
+}
+
+func (r *rateExecutor) New(prefix string) ScenarioRunner {
+	return NewRateRunner(r, prefix)
+}
+
+func (r *rateRunner) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *rateRunner) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan ScenarioResult) {
+	doneCh = make(chan ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed)}
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[RateCfg](config)
+	if err != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to parse scenario config %w", err)}
+		return
+	}
+	if cfg.Rate <= 0 {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("rate must be positive, but it is %v: %w", cfg.Rate, errors.ErrInvalid)}
+		return
+	}
+	var duration time.Duration
+	if len(cfg.Duration) > 0 {
+		if duration, err = time.ParseDuration(cfg.Duration); err != nil {
+			doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to parse duration %w", err)}
+			return
+		}
+	}
+	if duration <= 0 && cfg.Count <= 0 {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("either duration or count must be set: %w", errors.ErrInvalid)}
+		return
+	}
+	executor, ok := r.exec.Registry.Get(cfg.Action.Name)
+	if !ok {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to get executor %s: %w", cfg.Action.Name, errors.ErrNotExist)}
+		return
+	}
+	droppedMetric, _ := GetIntMetric(ctx, cfg.DroppedMetric)
+	lateMetric, _ := GetIntMetric(ctx, cfg.LateMetric)
+
+	var inFlight chan struct{}
+	if cfg.MaxInFlight > 0 {
+		inFlight = make(chan struct{}, cfg.MaxInFlight)
+	}
+	interval := time.Duration(float64(time.Second) / cfg.Rate)
+	start := time.Now()
+	var started, dropped, late int
+	for i := 0; cfg.Count <= 0 || i < cfg.Count; i++ {
+		scheduled := start.Add(time.Duration(i) * interval)
+		if duration > 0 && scheduled.Sub(start) >= duration {
+			break
+		}
+		if wait := time.Until(scheduled); wait > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(wait):
+			}
+		}
+		if ctx.Err() != nil || r.isFailed() {
+			break
+		}
+		if time.Since(scheduled) > interval {
+			late++
+			if lateMetric != nil {
+				lateMetric.Add(1)
+			}
+		}
+		if inFlight != nil {
+			select {
+			case inFlight <- struct{}{}:
+			default:
+				dropped++
+				if droppedMetric != nil {
+					droppedMetric.Add(1)
+				}
+				continue
+			}
+		}
+		started++
+		r.wg.Add(1)
+		go func(index int) {
+			defer r.wg.Done()
+			actionCtx := WithWorker(ctx)
+			result := <-executor.New(r.name).RunScenario(actionCtx, ExpandConfig(actionCtx, cfg.Action.Config))
+			if inFlight != nil {
+				<-inFlight
+			}
+			if err := result.Error(); err != nil {
+				r.lock.Lock()
+				if len(r.errs) < maxRateErrors {
+					r.errs[index] = err
+				}
+				r.errsNum++
+				r.failed = !cfg.SkipErrors
+				r.lock.Unlock()
+			}
+		}(i)
+	}
+	r.wg.Wait()
+	r.lock.Lock()
+	errs, errsNum := container.CopyMap(r.errs), r.errsNum
+	r.lock.Unlock()
+	r.exec.Logger.Infof("%s: %d actions started in %s, %d dropped, %d started late, %d failed",
+		r.name, started, time.Since(start).Round(time.Millisecond), dropped, late, errsNum)
+	doneCh <- &rateScenarioResult{name: r.name, errs: errs, errsNum: errsNum, skipErrors: cfg.SkipErrors}
+	return
+}
+
+func (r *rateRunner) isFailed() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.failed
+}
+
+// Ctx returns the context the rate runner was started with, the contexts of
+// the actions are not propagated as they may be started millions of times
+func (r *rateScenarioResult) Ctx(ctx context.Context) context.Context {
+	if r.skipErrors && len(r.errs) > 0 {
+		eList := container.CopyMap(ctx.Value(SkippedErrorsMap).(map[string]error))
+		for index, err := range r.errs {
+			eList[fmt.Sprintf("%s/action#%d", r.name, index)] = err
+		}
+		if more := r.errsNum - len(r.errs); more > 0 {
+			eList[fmt.Sprintf("%s/actions", r.name)] = moreErrors(more)
+		}
+		ctx = context.WithValue(ctx, SkippedErrorsMap, eList)
+	}
+	return ctx
+}
+
+func (r *rateScenarioResult) Error() error {
+	if r.skipErrors {
+		return nil
+	}
+	var err error
+	for index, actionErr := range r.errs {
+		if err != nil {
+			err = fmt.Errorf("%s,\n{failed action index[%d] caused by: %s}", err.Error(), index, actionErr)
+		} else {
+			err = fmt.Errorf("{failed action index[%d] caused by: %s}", index, actionErr)
+		}
+	}
+	if more := r.errsNum - len(r.errs); more > 0 {
+		err = fmt.Errorf("%s,\n{%d more actions failed}", err.Error(), more)
+	}
+	return err
+}
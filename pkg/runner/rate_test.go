@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateRunner(t *testing.T) {
+	logger := logging.NewLogger("test")
+	registry := newPauseRegistry(t, logger)
+	rateExec := &rateExecutor{name: RateRunName, Registry: registry, Logger: logger}
+
+	dropped := metrics.NewScalar[int64]()
+	ctx := context.WithValue(context.Background(), "dropped", MetricValue{Value: dropped, Type: INT})
+	cfg := model.ToScenarioConfig(&RateCfg{
+		Rate:          100,
+		Count:         20,
+		MaxInFlight:   1,
+		DroppedMetric: "dropped",
+		Action: model.Scenario{
+			Name:   PauseRunName,
+			Config: model.ToScenarioConfig(&PauseCfg{Value: "100ms"}),
+		},
+	})
+	start := time.Now()
+	result := <-rateExec.New("").RunScenario(ctx, cfg)
+	assert.NoError(t, result.Error())
+	// the starts are not delayed by the running actions, the sequential
+	// actions would take 2s
+	assert.Less(t, time.Since(start), 1500*time.Millisecond)
+	assert.Greater(t, dropped.Total(), int64(0))
+	assert.Less(t, dropped.Total(), int64(20))
+
+	result = <-rateExec.New("").RunScenario(ctx, model.ToScenarioConfig(&RateCfg{Count: 1}))
+	assert.Error(t, result.Error())
+
+	// only the first errors are kept
+	errs := map[int]error{}
+	for i := 0; i < maxRateErrors; i++ {
+		errs[i] = errors.ErrInternal
+	}
+	rr := &rateScenarioResult{name: "rate", errs: errs, errsNum: maxRateErrors + 5}
+	assert.ErrorContains(t, rr.Error(), "{5 more actions failed}")
+	rr.skipErrors = true
+	skippedCtx := rr.Ctx(context.WithValue(ctx, SkippedErrorsMap, map[string]error{}))
+	assert.Len(t, skippedCtx.Value(SkippedErrorsMap).(map[string]error), maxRateErrors+1)
+	// the errors metric counts all the failed actions
+	assert.Equal(t, int64(maxRateErrors+5), SkippedErrorsNum(skippedCtx))
+	res, ok := ContextMetricLookup(skippedCtx)(ErrorsMetricName)
+	assert.True(t, ok)
+	assert.Equal(t, int64(maxRateErrors+5), res.(metrics.IntMetricResult).Total)
+}
@@ -28,6 +28,10 @@ type (
 		Action     model.Scenario `yaml:"action" json:"action"`
 		Executor   string         `yaml:"executor" json:"executor"`
 		SkipErrors bool           `yaml:"skipErrors,omitempty" json:"skipErrors,omitempty"`
+		// MaxConcurrency and InFlightMetricName are passed to the parallel
+		// executor (see ParallelCfg), they are ignored by the sequence one
+		MaxConcurrency     int    `yaml:"maxConcurrency,omitempty" json:"maxConcurrency,omitempty"`
+		InFlightMetricName string `yaml:"inFlightMetricName,omitempty" json:"inFlightMetricName,omitempty"`
 	}
 )
 
@@ -119,25 +123,25 @@ func (r *repeatRunner) run(ctx context.Context, config *model.ScenarioConfig) (d
 			SkipErrors: cfg.SkipErrors,
 			Steps:      steps,
 		})
-		if scenarioResult = <-executor.New(r.name).RunScenario(ctx, secCfg); scenarioResult.Error() != nil {
+		if scenarioResult = <-executor.New(r.name).RunScenario(ctx, ExpandConfig(ctx, secCfg)); scenarioResult.Error() != nil {
 			doneCh <- scenarioResult
 			return
 		}
 	case ParallelRunName:
-		steps := make([]model.Scenario, cfg.Count)
-		for i := 0; i < cfg.Count; i++ {
-			steps[i] = cfg.Action
-		}
 		secCfg := model.ToScenarioConfig(&ParallelCfg{
-			SkipErrors: cfg.SkipErrors,
-			Steps:      steps,
+			SkipErrors:         cfg.SkipErrors,
+			Action:             &cfg.Action,
+			Count:              cfg.Count,
+			MaxConcurrency:     cfg.MaxConcurrency,
+			InFlightMetricName: cfg.InFlightMetricName,
 		})
-		if scenarioResult = <-executor.New(r.name).RunScenario(ctx, secCfg); scenarioResult.Error() != nil {
+		if scenarioResult = <-executor.New(r.name).RunScenario(ctx, ExpandConfig(ctx, secCfg)); scenarioResult.Error() != nil {
 			doneCh <- scenarioResult
 			return
 		}
-		if period > 0 {
-			time.Sleep(period)
+		if err = Sleep(ctx, period); err != nil {
+			doneCh <- &staticScenarioResult{ctx, err}
+			return
 		}
 	default:
 		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("unsupported executor name %s: %w", cfg.Executor, errors.ErrNotExist)}
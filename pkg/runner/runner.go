@@ -3,8 +3,11 @@ package runner
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/version"
 	"github.com/solarisdb/solaris/golibs/logging"
 )
 
@@ -25,9 +28,13 @@ type (
 	}
 
 	TestRunner struct {
-		Tests    *model.Config  `inject:""`
-		Registry *Registry      `inject:""`
-		Logger   logging.Logger `inject:""`
+		Tests    *model.Config    `inject:""`
+		Registry *Registry        `inject:""`
+		Exporter *MetricsExporter `inject:""`
+		Logger   logging.Logger   `inject:""`
+
+		// Summary contains the results of the tests, it is filled by Run
+		Summary RunSummary
 
 		doneCh chan error
 	}
@@ -35,6 +42,29 @@ type (
 
 const SkippedErrorsMap = "skippedErrors"
 
+// moreErrors is the skipped error standing for the errors which are not
+// kept in the SkippedErrorsMap, it counts them
+type moreErrors int
+
+func (e moreErrors) Error() string {
+	return fmt.Sprintf("%d more actions failed", int(e))
+}
+
+// SkippedErrorsNum returns the number of the skipped errors of the context,
+// including the ones which are not kept in the SkippedErrorsMap
+func SkippedErrorsNum(ctx context.Context) int64 {
+	skipped, _ := ctx.Value(SkippedErrorsMap).(map[string]error)
+	var res int64
+	for _, err := range skipped {
+		if more, ok := err.(moreErrors); ok {
+			res += int64(more)
+		} else {
+			res++
+		}
+	}
+	return res
+}
+
 func NewTestRunner() *TestRunner {
 	return &TestRunner{
 		doneCh: make(chan error, 1),
@@ -49,6 +79,7 @@ func (t *TestRunner) Run(ctx context.Context) <-chan error {
 	t.Logger.Infof("Start tests")
 	defer close(t.doneCh)
 
+	t.Summary = RunSummary{Version: version.Version, StartedAt: time.Now()}
 	i := 1
 	for _, test := range t.Tests.Tests {
 		t.Logger.Infof("Test#%d %q started", i, test.Name)
@@ -58,20 +89,52 @@ func (t *TestRunner) Run(ctx context.Context) <-chan error {
 			t.doneCh <- fmt.Errorf("cannot find scenario runner %s", scenarioCfg.Name)
 			return t.doneCh
 		}
+		start := time.Now()
 		ctx = context.WithValue(ctx, SkippedErrorsMap, map[string]error{})
-		if result := <-scRunner.New("").RunScenario(ctx, test.Scenario.Config); result.Error() != nil {
-			t.Logger.Errorf("Test#%d %q failed: %s", i, test.Name, result.Error().Error())
-		} else {
-			t.Logger.Infof("Test#%d %q passed", i, test.Name)
-			resCtx := result.Ctx(ctx)
+		ctx = context.WithValue(ctx, FixedMetricsMap, map[string]MetricValue{})
+		ctx = WithMetricLabel(ctx, TestLabel, test.Name)
+		scope := newTestScope()
+		result := <-scRunner.New("").RunScenario(context.WithValue(ctx, testScopeKey, scope), test.Scenario.Config)
+		resCtx := result.Ctx(ctx)
+		err := result.Error()
+		t.Exporter.Unregister(test.Name)
+		interrupted := ctx.Err() != nil
+		if interrupted {
+			t.Logger.Warnf("Test#%d %q is interrupted, flushing the partial results", i, test.Name)
+			resCtx = scope.finalize(resCtx)
+			for name, mv := range resCtx.Value(FixedMetricsMap).(map[string]MetricValue) {
+				if res, ok := mv.Result(); ok {
+					t.Logger.Infof("Metric %q: %v", name, res)
+				}
+			}
+			err = fmt.Errorf("test is interrupted: %w", ctx.Err())
+		}
+		if err == nil {
 			skippedErrors := resCtx.Value(SkippedErrorsMap).(map[string]error)
 			for runner, skippedError := range skippedErrors {
 				t.Logger.Infof("skipped error: %s - %s", runner, skippedError.Error())
 			}
+			err = CheckAssertions(test.Thresholds, ContextMetricLookup(resCtx))
 		}
+		if err != nil {
+			t.Logger.Errorf("Test#%d %q failed: %s", i, test.Name, err.Error())
+			t.Summary.Failed++
+		} else {
+			t.Logger.Infof("Test#%d %q passed", i, test.Name)
+			t.Summary.Passed++
+		}
+		t.Summary.Tests = append(t.Summary.Tests, newTestSummary(test.Name, start, resCtx, err))
 		i++
+		if interrupted {
+			break
+		}
 	}
+	t.Summary.FinishedAt = time.Now()
 	t.Logger.Infof("Tests ended")
+	if t.Summary.Failed > 0 {
+		t.doneCh <- fmt.Errorf("%d of %d tests failed: %s", t.Summary.Failed, len(t.Summary.Tests), strings.Join(t.Summary.FailedTests(), ", "))
+		return t.doneCh
+	}
 	t.doneCh <- nil
 	return t.doneCh
 }
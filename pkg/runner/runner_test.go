@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestRunner_Interrupted(t *testing.T) {
+	logger := logging.NewLogger("test")
+	registry := newPauseRegistry(t, logger)
+	assert.NoError(t, registry.Register(&sequenceExecutor{Registry: registry, Logger: logger}))
+	assert.NoError(t, registry.Register(&metricsCreateExecutor{name: MetricsCreateRunName, Registry: registry, Logger: logger}))
+	finalized := make(chan struct{})
+	assert.NoError(t, registry.Register(&finalizerExecutor{finalized: finalized}))
+
+	test := model.Test{
+		Name: "interrupted",
+		Scenario: model.Scenario{
+			Name: SequenceRunName,
+			Config: model.ToScenarioConfig(&SequenceCfg{
+				Steps: []model.Scenario{
+					{Name: MetricsCreateRunName, Config: model.ToScenarioConfig(&MetricsCreateCfg{Metrics: map[MetricsType][]string{INT: {"counter"}}})},
+					{Name: "finalizer"},
+					{Name: PauseRunName, Config: model.ToScenarioConfig(&PauseCfg{Value: "1h"})},
+				},
+			}),
+		},
+	}
+	tr := NewTestRunner()
+	tr.Tests = &model.Config{Tests: []model.Test{test, test}}
+	tr.Registry = registry
+	tr.Logger = logger
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, <-tr.Run(ctx))
+	<-finalized
+	assert.Equal(t, 1, len(tr.Summary.Tests))
+	assert.Equal(t, TestFailed, tr.Summary.Tests[0].Status)
+	assert.Contains(t, tr.Summary.Tests[0].Metrics, "counter")
+}
+
+type finalizerExecutor struct {
+	finalized chan struct{}
+}
+
+func (f *finalizerExecutor) Name() string {
+	return "finalizer"
+}
+
+func (f *finalizerExecutor) New(prefix string) ScenarioRunner {
+	return f
+}
+
+func (f *finalizerExecutor) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan ScenarioResult {
+	AddFinalizer(ctx, func(fCtx context.Context) {
+		if _, ok := GetIntMetric(fCtx, "counter"); ok && fCtx.Err() == nil {
+			close(f.finalized)
+		}
+	})
+	doneCh := make(chan ScenarioResult, 1)
+	doneCh <- NewStaticScenarioResult(ctx, nil)
+	close(doneCh)
+	return doneCh
+}
@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/solarisdb/solaris/golibs/container"
+)
+
+type (
+	// testScope keeps the state of the running test which must survive the
+	// test interruption: the finalizers and all the metrics created in the
+	// test. The scenario results are lost when the test is interrupted, so
+	// the TestRunner uses the scope to flush the partial results.
+	testScope struct {
+		lock       sync.Mutex
+		finalizers []Finalizer
+		metrics    map[string]MetricValue
+	}
+
+	// Finalizer is called with the context containing the metrics of the
+	// interrupted test, the context is not closed until the finalize timeout
+	Finalizer func(ctx context.Context)
+)
+
+const (
+	testScopeKey = "testScope"
+	// finalizeTimeout is how long the finalizers of the interrupted test may run
+	finalizeTimeout = 30 * time.Second
+)
+
+func newTestScope() *testScope {
+	return &testScope{metrics: map[string]MetricValue{}}
+}
+
+// AddFinalizer registers the function called if the test is interrupted,
+// it does nothing if the context does not belong to a test
+func AddFinalizer(ctx context.Context, f Finalizer) {
+	if scope, ok := ctx.Value(testScopeKey).(*testScope); ok {
+		scope.lock.Lock()
+		scope.finalizers = append(scope.finalizers, f)
+		scope.lock.Unlock()
+	}
+}
+
+func trackMetric(ctx context.Context, name string, mv MetricValue) {
+	if scope, ok := ctx.Value(testScopeKey).(*testScope); ok {
+		scope.lock.Lock()
+		scope.metrics[name] = mv
+		scope.lock.Unlock()
+	}
+}
+
+// TrackedMetrics returns all the metrics created in the test
+func TrackedMetrics(ctx context.Context) map[string]MetricValue {
+	if scope, ok := ctx.Value(testScopeKey).(*testScope); ok {
+		scope.lock.Lock()
+		defer scope.lock.Unlock()
+		return container.CopyMap(scope.metrics)
+	}
+	return nil
+}
+
+// finalize calls the finalizers in the reverse order with the context
+// containing the metrics created in the test. The returned context holds
+// the created metrics as the fixed ones, so they get to the summary.
+func (s *testScope) finalize(ctx context.Context) context.Context {
+	s.lock.Lock()
+	finalizers := container.SliceCopy(s.finalizers)
+	fixed := container.CopyMap(s.metrics)
+	s.lock.Unlock()
+
+	for name, mv := range fixed {
+		ctx = context.WithValue(ctx, name, mv)
+	}
+	ctx = context.WithValue(ctx, FixedMetricsMap, fixed)
+	fCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finalizeTimeout)
+	defer cancel()
+	for i := len(finalizers) - 1; i >= 0; i-- {
+		finalizers[i](fCtx)
+	}
+	return ctx
+}
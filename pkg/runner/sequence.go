@@ -26,6 +26,8 @@ type (
 		SkipErrors        bool             `yaml:"skipErrors,omitempty" json:"skipErrors,omitempty"`
 		Steps             []model.Scenario `yaml:"steps" json:"steps"`
 		StepTimeoutMetric string           `yaml:"stepTimeoutMetric,omitempty" json:"stepTimeoutMetric,omitempty"`
+		// steps duration percentiles
+		StepLatencyMetric string `yaml:"stepLatencyMetric,omitempty" json:"stepLatencyMetric,omitempty"`
 		// how many times a step is called per second
 		StepRpsMetric string `yaml:"stepRpsMetric,omitempty" json:"stepRpsMetric,omitempty"`
 		// steps rps distribution
@@ -93,15 +95,19 @@ func (r *sequenceRunner) run(mctx context.Context, config *model.ScenarioConfig)
 			return
 		}
 		timeOutM, _ := GetDurationMetric(ctx, cfg.StepTimeoutMetric)
+		latencyM, _ := GetHistogramMetric(ctx, cfg.StepLatencyMetric)
 		rpsM, _ := GetRateMetric(ctx, cfg.StepRpsMetric)
 		durRpsM, _ := GetRateMetric(ctx, cfg.StepRpsDistMetric)
 		start := time.Now()
-		stepResCh := stepRunner.New(r.name).RunScenario(ctx, step.Config)
+		stepResCh := stepRunner.New(r.name).RunScenario(ctx, ExpandConfig(ctx, step.Config))
 		stepRes = append(stepRes, <-stepResCh)
 		dur := time.Since(start)
 		if timeOutM != nil {
 			timeOutM.Add(dur.Nanoseconds())
 		}
+		if latencyM != nil {
+			latencyM.Add(dur.Nanoseconds())
+		}
 		if rpsM != nil {
 			rpsM.Add(1, 0)
 		}
@@ -1,15 +1,18 @@
 package solaris
 
 import (
+	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/payload"
 	"github.com/solarisdb/perftests/pkg/runner"
 	"github.com/solarisdb/solaris/api/gen/solaris/v1"
-	"github.com/solarisdb/solaris/golibs/container"
 	"github.com/solarisdb/solaris/golibs/errors"
 	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/solarisdb/solaris/golibs/ulidutils"
 
 	"context"
 )
@@ -25,15 +28,34 @@ type (
 		Registry *runner.Registry `inject:""`
 		EnvCfg   *model.Config    `inject:""`
 		Logger   logging.Logger   `inject:""`
+
+		// generators caches the payload generators by their configs, so
+		// the pools and the files are not loaded by every run
+		generators sync.Map
 	}
 
 	AppendCfg struct {
-		MessageSize         int    `yaml:"messageSize" json:"messageSize"`
+		MessageSize int `yaml:"messageSize" json:"messageSize"`
+		// Payload describes how the message payloads are generated, the
+		// constant payload of the MessageSize is used if not set
+		Payload *payload.Config `yaml:"payload,omitempty" json:"payload,omitempty"`
+		// Verify enables sealing every payload with the writer ID, the
+		// sequence number and the checksum, so the log can be checked
+		// by the solaris.verify scenario. A verified batch failed with a
+		// tolerated error is re-sent, so the records appended by the failed
+		// call may be reported as Duplicated, but never as Missing
+		Verify bool `yaml:"verify,omitempty" json:"verify,omitempty"`
+		// StampTime enables prefixing every payload with the send time, so
+		// the solaris.tail scenario can measure the append-to-read lag
+		StampTime           bool   `yaml:"stampTime,omitempty" json:"stampTime,omitempty"`
 		BatchSize           int    `yaml:"batchSize" json:"batchSize"`
 		Number              int    `yaml:"number" json:"number"`
 		TimeoutMetricName   string `yaml:"timeoutMetricName,omitempty" json:"timeoutMetricName,omitempty"`
+		LatencyMetricName   string `yaml:"latencyMetricName,omitempty" json:"latencyMetricName,omitempty"`
 		MsgsRateMetricName  string `yaml:"msgsRateMetricName,omitempty" json:"msgsRateMetricName,omitempty"`
 		BytesRateMetricName string `yaml:"bytesRateMetricName,omitempty" json:"bytesRateMetricName,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
 	}
 )
 
@@ -87,11 +109,14 @@ func (r *appendMsg) run(ctx context.Context, config *model.ScenarioConfig) (done
 		cfg.Number = 1
 	}
 
-	//prepareMessage
-	payl := make([]byte, cfg.MessageSize, cfg.MessageSize)
-	container.SliceFill(payl, 'z')
+	gen, err := r.exec.generator(cfg.Payload, cfg.MessageSize)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to create payload generator: %w", err))
+		return
+	}
 	records := make([]*solaris.Record, cfg.BatchSize, cfg.BatchSize)
-	container.SliceFill(records, &solaris.Record{Payload: payl})
+	writer := ulidutils.NewID()
+	total := int64(cfg.Number * cfg.BatchSize)
 
 	clnt, _ := ctx.Value(solarisClnt).(solaris.ServiceClient)
 	if clnt == nil {
@@ -99,6 +124,12 @@ func (r *appendMsg) run(ctx context.Context, config *model.ScenarioConfig) (done
 		return
 	}
 
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
 	log, _ := ctx.Value(solarisLog).(string)
 	if len(log) == 0 {
 		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris log not found"))
@@ -106,31 +137,80 @@ func (r *appendMsg) run(ctx context.Context, config *model.ScenarioConfig) (done
 	}
 
 	toMetric, _ := runner.GetDurationMetric(ctx, cfg.TimeoutMetricName)
+	latencyMetric, _ := runner.GetHistogramMetric(ctx, cfg.LatencyMetricName)
 	bytesInSecMetric, _ := runner.GetRateMetric(ctx, cfg.BytesRateMetricName)
 	msgsInSecMetric, _ := runner.GetRateMetric(ctx, cfg.MsgsRateMetricName)
 	req := &solaris.AppendRecordsRequest{
 		LogID:   log,
 		Records: records,
 	}
+	var size int
+	resend := false
 	for i := 0; i < cfg.Number; i++ {
-		start := time.Now()
-		_, err = clnt.AppendRecords(ctx, req)
+		if !resend {
+			size = 0
+			now := time.Now()
+			for j := range records {
+				payl := gen.Next()
+				if cfg.StampTime {
+					payl = payload.Stamp(now, payl)
+				}
+				if cfg.Verify {
+					payl = payload.Seal(writer, int64(i*cfg.BatchSize+j), total, payl)
+				}
+				records[j] = &solaris.Record{Payload: payl}
+				size += len(records[j].Payload)
+			}
+		}
+		resend = false
+		_, dur, err := timedCall(ctx, policy, clnt.AppendRecords, req)
 		if err != nil {
+			if policy.tolerate(ctx, err) {
+				// the sealed sequence numbers must be written, so the
+				// same verified batch is re-sent instead of skipped
+				if cfg.Verify {
+					resend = true
+					i--
+				}
+				continue
+			}
 			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to append records: %w", err))
 			return
 		}
-		dur := time.Since(start)
 		if toMetric != nil {
 			toMetric.Add(dur.Nanoseconds())
 		}
+		if latencyMetric != nil {
+			latencyMetric.Add(dur.Nanoseconds())
+		}
 		if msgsInSecMetric != nil {
 			msgsInSecMetric.Add(float64(cfg.BatchSize), dur)
 		}
 		if bytesInSecMetric != nil {
-			bytesInSecMetric.Add(float64(cfg.BatchSize*cfg.MessageSize), dur)
+			bytesInSecMetric.Add(float64(size), dur)
 		}
 	}
 
 	doneCh <- runner.NewStaticScenarioResult(ctx, nil)
 	return
 }
+
+// generator returns the cached payload generator of the config or creates a new one
+func (r *appendMsgExecutor) generator(cfg *payload.Config, messageSize int) (payload.Generator, error) {
+	key, err := json.Marshal(struct {
+		Cfg  *payload.Config
+		Size int
+	}{cfg, messageSize})
+	if err != nil {
+		return nil, err
+	}
+	if gen, ok := r.generators.Load(string(key)); ok {
+		return gen.(payload.Generator), nil
+	}
+	gen, err := payload.New(cfg, messageSize)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := r.generators.LoadOrStore(string(key), gen)
+	return actual.(payload.Generator), nil
+}
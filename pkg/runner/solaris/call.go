@@ -0,0 +1,236 @@
+package solaris
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	// CallCfg describes how the executor makes the Solaris calls, it is
+	// embedded into the configs of all the solaris executors. The zero value
+	// means the calls with no deadline and no retries.
+	CallCfg struct {
+		// Timeout is the deadline of every call attempt, e.g. "5s"
+		Timeout string    `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+		Retry   *RetryCfg `yaml:"retry,omitempty" json:"retry,omitempty"`
+		// RetriesMetricName is the INT metric counting the retried attempts
+		RetriesMetricName string `yaml:"retriesMetricName,omitempty" json:"retriesMetricName,omitempty"`
+		// CallTimeoutsMetricName is the INT metric counting the attempts
+		// failed because of the Timeout
+		CallTimeoutsMetricName string `yaml:"callTimeoutsMetricName,omitempty" json:"callTimeoutsMetricName,omitempty"`
+		// ErrorCodesMetricName is the COUNTERS metric counting the failed
+		// calls (after the retries) by their gRPC status codes, e.g. Unavailable
+		ErrorCodesMetricName string `yaml:"errorCodesMetricName,omitempty" json:"errorCodesMetricName,omitempty"`
+		// ContinueOn are the gRPC status codes (e.g. "UNAVAILABLE") of the
+		// errors the executor counts and continues with the next operation
+		// on, instead of failing. ContinueOnAll means any error. The next
+		// operation is delayed by the retry backoff doubled on every error
+		// in a row.
+		ContinueOn []string `yaml:"continueOn,omitempty" json:"continueOn,omitempty"`
+		// ContinueMaxInRow is the max number of the ContinueOn errors in a
+		// row, the executor fails on the next one, 100 by default
+		ContinueMaxInRow int `yaml:"continueMaxInRow,omitempty" json:"continueMaxInRow,omitempty"`
+	}
+
+	// RetryCfg describes the retries of the failed calls, the delay before
+	// every next attempt is doubled starting from the Backoff up to the
+	// MaxBackoff, with the random jitter of up to the half of the delay.
+	// Note, the retried appends may duplicate the records if the failed
+	// attempt is applied by the server.
+	RetryCfg struct {
+		// MaxAttempts is the max number of the attempts of every call, including the first one
+		MaxAttempts int `yaml:"maxAttempts" json:"maxAttempts"`
+		// Backoff is the delay before the first retry, 100ms by default
+		Backoff string `yaml:"backoff,omitempty" json:"backoff,omitempty"`
+		// MaxBackoff is the max delay between the attempts, 5s by default
+		MaxBackoff string `yaml:"maxBackoff,omitempty" json:"maxBackoff,omitempty"`
+		// Codes are the gRPC status codes of the retried errors, e.g. "UNAVAILABLE",
+		// UNAVAILABLE, RESOURCE_EXHAUSTED and DEADLINE_EXCEEDED by default
+		Codes []string `yaml:"codes,omitempty" json:"codes,omitempty"`
+	}
+
+	// callPolicy makes the calls as described by the CallCfg, it is used
+	// by one runner at a time
+	callPolicy struct {
+		timeout      time.Duration
+		maxAttempts  int
+		backoff      time.Duration
+		maxBackoff   time.Duration
+		codes        map[codes.Code]bool
+		continueOn   map[codes.Code]bool
+		continueAll  bool
+		continueMax  int
+		failedInRow  int
+		retries      *metrics.Scalar[int64]
+		callTimeouts *metrics.Scalar[int64]
+		errorCodes   *metrics.Counters
+	}
+)
+
+const (
+	// ContinueOnAll in the ContinueOn means any error
+	ContinueOnAll = "ALL"
+
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultRetryMaxBackoff = 5 * time.Second
+	defaultContinueMax     = 100
+)
+
+var defaultRetryCodes = map[codes.Code]bool{codes.Unavailable: true, codes.ResourceExhausted: true, codes.DeadlineExceeded: true}
+
+// newCallPolicy returns the policy of the config with the metrics found in the context
+func newCallPolicy(ctx context.Context, cfg CallCfg) (*callPolicy, error) {
+	p := &callPolicy{maxAttempts: 1, backoff: defaultRetryBackoff, maxBackoff: defaultRetryMaxBackoff}
+	var err error
+	if p.timeout, err = parseOptDuration(cfg.Timeout, 0); err != nil {
+		return nil, fmt.Errorf("failed to parse call timeout: %w", err)
+	}
+	if cfg.Retry != nil {
+		p.maxAttempts = max(cfg.Retry.MaxAttempts, 1)
+		if p.backoff, err = parseOptDuration(cfg.Retry.Backoff, defaultRetryBackoff); err != nil {
+			return nil, fmt.Errorf("failed to parse retry backoff: %w", err)
+		}
+		if p.maxBackoff, err = parseOptDuration(cfg.Retry.MaxBackoff, defaultRetryMaxBackoff); err != nil {
+			return nil, fmt.Errorf("failed to parse retry max backoff: %w", err)
+		}
+		p.codes = defaultRetryCodes
+		if len(cfg.Retry.Codes) > 0 {
+			if p.codes, err = parseCodes(cfg.Retry.Codes); err != nil {
+				return nil, fmt.Errorf("invalid retry codes: %w", err)
+			}
+		}
+	}
+	for _, name := range cfg.ContinueOn {
+		p.continueAll = p.continueAll || name == ContinueOnAll
+	}
+	if !p.continueAll {
+		if p.continueOn, err = parseCodes(cfg.ContinueOn); err != nil {
+			return nil, fmt.Errorf("invalid continueOn codes: %w", err)
+		}
+	}
+	if p.continueMax = cfg.ContinueMaxInRow; p.continueMax <= 0 {
+		p.continueMax = defaultContinueMax
+	}
+	p.retries, _ = runner.GetIntMetric(ctx, cfg.RetriesMetricName)
+	p.callTimeouts, _ = runner.GetIntMetric(ctx, cfg.CallTimeoutsMetricName)
+	p.errorCodes, _ = runner.GetCountersMetric(ctx, cfg.ErrorCodesMetricName)
+	return p, nil
+}
+
+// tolerated returns true if the executor should continue after the failed call
+func (p *callPolicy) tolerated(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return p.continueAll || p.continueOn[status.Code(err)]
+}
+
+// tolerate returns true if the executor should continue after the failed
+// call, it pauses for the backoff doubled on every failed call in a row, so
+// the persistent errors do not make the executor hammer the server. The
+// failed calls in a row over the ContinueMaxInRow are not tolerated.
+func (p *callPolicy) tolerate(ctx context.Context, err error) bool {
+	if !p.tolerated(ctx, err) || p.failedInRow >= p.continueMax {
+		return false
+	}
+	delay := p.backoff
+	for i := 0; i < p.failedInRow && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+	p.failedInRow++
+	return runner.Sleep(ctx, min(delay, p.maxBackoff)) == nil
+}
+
+func parseCodes(names []string) (map[codes.Code]bool, error) {
+	res := make(map[codes.Code]bool, len(names))
+	for _, name := range names {
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil {
+			return nil, fmt.Errorf("unknown status code %q: %w", name, errors.ErrInvalid)
+		}
+		res[code] = true
+	}
+	return res, nil
+}
+
+// call makes the call of the client method, retrying it as described by
+// the policy. The error of the failed call is counted by its status code.
+func call[Req, Res any](ctx context.Context, p *callPolicy, method func(context.Context, Req, ...grpc.CallOption) (Res, error), req Req) (Res, error) {
+	res, _, err := timedCall(ctx, p, method, req)
+	return res, err
+}
+
+// timedCall is the call which returns the duration of the last attempt,
+// the failed attempts and the delays between them are not measured, so
+// the latency metrics of the executors are the latency of the succeeded calls
+func timedCall[Req, Res any](ctx context.Context, p *callPolicy, method func(context.Context, Req, ...grpc.CallOption) (Res, error), req Req) (Res, time.Duration, error) {
+	backoff := p.backoff
+	for n := 1; ; n++ {
+		start := time.Now()
+		res, err := attempt(ctx, p, method, req)
+		dur := time.Since(start)
+		if err == nil {
+			p.failedInRow = 0
+			return res, dur, nil
+		}
+		if ctx.Err() != nil {
+			return res, dur, err
+		}
+		if n >= p.maxAttempts || !p.codes[status.Code(err)] {
+			if p.errorCodes != nil {
+				p.errorCodes.Add(status.Code(err).String(), 1)
+			}
+			return res, dur, err
+		}
+		if p.retries != nil {
+			p.retries.Add(1)
+		}
+		delay := backoff + rand.N(backoff/2+1)
+		select {
+		case <-ctx.Done():
+			return res, dur, err
+		case <-time.After(delay):
+		}
+		backoff = min(2*backoff, p.maxBackoff)
+	}
+}
+
+// attempt makes one attempt of the call with the policy deadline
+func attempt[Req, Res any](ctx context.Context, p *callPolicy, method func(context.Context, Req, ...grpc.CallOption) (Res, error), req Req) (Res, error) {
+	if p.timeout <= 0 {
+		return method(ctx, req)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	res, err := method(callCtx, req)
+	if err != nil && ctx.Err() == nil && callCtx.Err() == context.DeadlineExceeded && p.callTimeouts != nil {
+		p.callTimeouts.Add(1)
+	}
+	return res, err
+}
+
+// parseOptDuration parses the non-negative duration, it returns the
+// default value if the duration is not set
+func parseOptDuration(s string, defaultValue time.Duration) (time.Duration, error) {
+	if len(s) == 0 {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration %s is negative: %w", s, errors.ErrInvalid)
+	}
+	return d, nil
+}
@@ -0,0 +1,112 @@
+package solaris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCall(t *testing.T) {
+	retries := metrics.NewScalar[int64]()
+	timeouts := metrics.NewScalar[int64]()
+	ctx := context.WithValue(context.Background(), "retries", runner.MetricValue{Value: retries, Type: runner.INT})
+	ctx = context.WithValue(ctx, "timeouts", runner.MetricValue{Value: timeouts, Type: runner.INT})
+	policy, err := newCallPolicy(ctx, CallCfg{
+		Timeout:                "10ms",
+		Retry:                  &RetryCfg{MaxAttempts: 3, Backoff: "1ms"},
+		RetriesMetricName:      "retries",
+		CallTimeoutsMetricName: "timeouts",
+	})
+	assert.NoError(t, err)
+
+	var attempts int
+	method := func(ctx context.Context, req string, _ ...grpc.CallOption) (string, error) {
+		attempts++
+		switch req {
+		case "stuck":
+			<-ctx.Done()
+			return "", status.FromContextError(ctx.Err()).Err()
+		case "unavailable":
+			if attempts < 3 {
+				return "", status.Error(codes.Unavailable, "unavailable")
+			}
+		case "invalid":
+			return "", status.Error(codes.InvalidArgument, "invalid")
+		}
+		return req, nil
+	}
+
+	res, err := call(ctx, policy, method, "unavailable")
+	assert.NoError(t, err)
+	assert.Equal(t, "unavailable", res)
+	assert.Equal(t, int64(2), retries.Sum())
+
+	attempts = 0
+	_, err = call(ctx, policy, method, "invalid")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, 1, attempts)
+
+	attempts = 0
+	_, err = call(ctx, policy, method, "stuck")
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, int64(3), timeouts.Sum())
+	assert.Equal(t, int64(4), retries.Sum())
+
+	errorCodes := metrics.NewCounters()
+	ctx = context.WithValue(ctx, "errors", runner.MetricValue{Value: errorCodes, Type: runner.COUNTERS})
+	policy, err = newCallPolicy(ctx, CallCfg{ErrorCodesMetricName: "errors", ContinueOn: []string{"INVALID_ARGUMENT"}})
+	assert.NoError(t, err)
+	_, err = call(ctx, policy, method, "invalid")
+	assert.True(t, policy.tolerated(ctx, err))
+	attempts = 0
+	_, err = call(ctx, policy, method, "unavailable")
+	assert.False(t, policy.tolerated(ctx, err))
+	assert.Equal(t, metrics.GetCountersMetricResult(errorCodes).Counts, map[string]int64{"InvalidArgument": 1, "Unavailable": 1})
+	policy, err = newCallPolicy(ctx, CallCfg{ContinueOn: []string{ContinueOnAll}})
+	assert.NoError(t, err)
+	assert.True(t, policy.tolerated(ctx, status.Error(codes.Internal, "internal")))
+
+	_, err = newCallPolicy(ctx, CallCfg{ContinueOn: []string{"UNKNOWN_CODE"}})
+	assert.Error(t, err)
+	_, err = newCallPolicy(ctx, CallCfg{Retry: &RetryCfg{Codes: []string{"UNKNOWN_CODE"}}})
+	assert.Error(t, err)
+	_, err = newCallPolicy(ctx, CallCfg{Timeout: "1 second"})
+	assert.Error(t, err)
+	_, err = newCallPolicy(ctx, CallCfg{Timeout: "-1s"})
+	assert.Error(t, err)
+	_, err = newCallPolicy(ctx, CallCfg{Retry: &RetryCfg{MaxAttempts: 2, Backoff: "-10ms"}})
+	assert.Error(t, err)
+
+	// the errors in a row are tolerated up to the limit
+	policy, err = newCallPolicy(ctx, CallCfg{Retry: &RetryCfg{MaxAttempts: 1, Backoff: "1ms"},
+		ContinueOn: []string{"INVALID_ARGUMENT"}, ContinueMaxInRow: 2})
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = call(ctx, policy, method, "invalid")
+		assert.True(t, policy.tolerate(ctx, err))
+	}
+	_, err = call(ctx, policy, method, "invalid")
+	assert.False(t, policy.tolerate(ctx, err))
+	_, err = call(ctx, policy, method, "ok")
+	assert.NoError(t, err)
+	_, err = call(ctx, policy, method, "invalid")
+	assert.True(t, policy.tolerate(ctx, err))
+
+	// the duration of the succeeded attempt only is returned
+	policy, err = newCallPolicy(ctx, CallCfg{Retry: &RetryCfg{MaxAttempts: 3, Backoff: "200ms"}})
+	assert.NoError(t, err)
+	attempts = 0
+	start := time.Now()
+	_, dur, err := timedCall(ctx, policy, method, "unavailable")
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	assert.Less(t, dur, 200*time.Millisecond)
+}
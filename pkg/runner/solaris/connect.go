@@ -1,19 +1,18 @@
 package solaris
 
 import (
-	"crypto/tls"
 	"fmt"
-	"os"
-	"strings"
+	"strconv"
 
+	"github.com/solarisdb/perftests/pkg/client"
+	"github.com/solarisdb/perftests/pkg/inmem"
+	"github.com/solarisdb/perftests/pkg/metrics"
 	"github.com/solarisdb/perftests/pkg/model"
 	"github.com/solarisdb/perftests/pkg/runner"
 	"github.com/solarisdb/solaris/api/gen/solaris/v1"
 	"github.com/solarisdb/solaris/golibs/errors"
 	"github.com/solarisdb/solaris/golibs/logging"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
 
 	"context"
 )
@@ -26,24 +25,45 @@ type (
 
 	connectExecutor struct {
 		name     string
-		Registry *runner.Registry `inject:""`
-		Logger   logging.Logger   `inject:""`
+		Registry *runner.Registry        `inject:""`
+		Exporter *runner.MetricsExporter `inject:""`
+		Logger   logging.Logger          `inject:""`
 	}
 
 	ConnectCfg struct {
-		Address       string `yaml:"address" json:"address"`
-		EnvVarAddress string `yaml:"envVarAddress" json:"envVarAddress"`
+		Address string `yaml:"address" json:"address"`
+		// Addresses are the addresses of the other Solaris instances the
+		// load is spread over together with the Address
+		Addresses []string `yaml:"addresses,omitempty" json:"addresses,omitempty"`
+		// EnvVarAddress is the env variable with the comma-separated addresses,
+		// the Address and Addresses are ignored if it is set
+		EnvVarAddress string          `yaml:"envVarAddress" json:"envVarAddress"`
+		Grpc          *client.GrpcCfg `yaml:"grpc,omitempty" json:"grpc,omitempty"`
+		// Channels is the number of the connections to every address, 1 by default
+		Channels int `yaml:"channels,omitempty" json:"channels,omitempty"`
+		// Balancing is how the requests are spread over the connections,
+		// client.BalancingRoundRobin (default) or client.BalancingAffinity.
+		// The affinity key is the worker the request is made by (see
+		// runner.WithWorker), so every worker sticks to its connection.
+		Balancing string `yaml:"balancing,omitempty" json:"balancing,omitempty"`
+		// ChannelMetricName is the prefix of the INT metrics counting the
+		// requests of every connection, the metric of the i-th connection
+		// is named "<ChannelMetricName>.<i>"
+		ChannelMetricName string `yaml:"channelMetricName,omitempty" json:"channelMetricName,omitempty"`
+		// InMem describes the faults injected by the in-memory servers of the
+		// "inmem://<name>" addresses, which let the scenarios run with no Solaris
+		InMem *inmem.Config `yaml:"inmem,omitempty" json:"inmem,omitempty"`
 	}
 
 	connectScenarioResult struct {
-		svc solaris.ServiceClient
+		svc     solaris.ServiceClient
+		metrics map[string]runner.MetricValue
 	}
 )
 
 const (
-	solarisClnt    = "solarisClnt"
-	ConnectName    = "solaris.connect"
-	maxGrpcMsgSize = 100 * 1024 * 1024 //100MB
+	solarisClnt = "solarisClnt"
+	ConnectName = "solaris.connect"
 )
 
 func NewConnect(exec *connectExecutor, prefix string) runner.ScenarioRunner {
@@ -88,64 +108,97 @@ func (r *connect) run(ctx context.Context, config *model.ScenarioConfig) (doneCh
 		return
 	}
 
-	address := cfg.Address
-	if len(cfg.EnvVarAddress) > 0 {
-		rawEnv := os.Environ()
-		for _, v := range rawEnv {
-			parts := strings.SplitN(v, "=", 2)
-			if len(parts) != 2 {
-				continue
-			}
-			if parts[0] == cfg.EnvVarAddress {
-				address = parts[1]
-				break
+	addresses := client.Addresses(append([]string{cfg.Address}, cfg.Addresses...), cfg.EnvVarAddress)
+	if len(addresses) == 0 {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("no address to connect to: %w", errors.ErrInvalid))
+		return
+	}
+	for _, address := range addresses {
+		if inmem.IsAddress(address) {
+			if err = inmem.Serve(address).SetConfig(cfg.InMem); err != nil {
+				doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid in-memory server config: %w", err))
+				return
 			}
 		}
 	}
-
-	conn, err := r.dial(address)
-	if err != nil {
-		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to dial to address %s: %w", address, err))
+	channels := max(cfg.Channels, 1)
+	var affinityKey func(ctx context.Context) string
+	switch cfg.Balancing {
+	case "", client.BalancingRoundRobin:
+	case client.BalancingAffinity:
+		affinityKey = workerAffinityKey
+	default:
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("unknown balancing %q: %w", cfg.Balancing, errors.ErrInvalid))
 		return
 	}
-
-	client := solaris.NewServiceClient(conn)
-	doneCh <- &connectScenarioResult{
-		client,
+	if ctx.Value(solarisClnt) != nil {
+		// the client of the outer connect is kept by the context,
+		// so no connections are dialed just to be discarded
+		r.exec.Logger.Debugf("%s: the solaris client is already connected", r.name)
+		doneCh <- runner.NewStaticScenarioResult(ctx, nil)
+		return
 	}
-	return
-}
 
-func (r *connect) dial(addr string) (grpc.ClientConnInterface, error) {
-	initOpts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
-		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxGrpcMsgSize), grpc.MaxCallSendMsgSize(maxGrpcMsgSize)),
+	var conns []*grpc.ClientConn
+	for _, address := range addresses {
+		for i := 0; i < channels; i++ {
+			conn, err := client.Dial(ctx, address, cfg.Grpc)
+			if err != nil {
+				for _, c := range conns {
+					_ = c.Close()
+				}
+				doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to dial to address %s: %w", address, err))
+				return
+			}
+			conns = append(conns, conn)
+		}
 	}
-	if isTls(addr) {
-		// overwriting the TransportCredentials by TLS with default config
-		initOpts[0] = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))
+	if len(conns) == 1 && len(cfg.ChannelMetricName) == 0 {
+		doneCh <- &connectScenarioResult{svc: solaris.NewServiceClient(conns[0])}
+		return
 	}
-	return grpc.Dial(addr, initOpts...)
-}
 
-func isTls(addr string) bool {
-	idx := strings.LastIndex(addr, ":")
-	if idx == -1 {
-		return false // insecure
+	res := &connectScenarioResult{metrics: map[string]runner.MetricValue{}}
+	var onCall func(idx int)
+	if len(cfg.ChannelMetricName) > 0 {
+		counters := make([]*metrics.Scalar[int64], len(conns))
+		for i := range counters {
+			counters[i] = metrics.NewScalar[int64]()
+			res.metrics[fmt.Sprintf("%s.%d", cfg.ChannelMetricName, i)] = runner.MetricValue{Value: counters[i], Type: runner.INT}
+		}
+		onCall = func(idx int) {
+			counters[idx].Add(1)
+		}
+	}
+	for name, mv := range res.metrics {
+		r.exec.Exporter.Register(ctx, name, mv)
 	}
-	s1 := strings.Trim(addr[idx+1:], " ")
-	return s1 == "443"
+	r.exec.Logger.Debugf("%s: %d channels to %v", r.name, len(conns), addresses)
+	res.svc = solaris.NewServiceClient(client.NewPool(conns, affinityKey, onCall))
+	doneCh <- res
+	return
 }
 
 func (r *connectScenarioResult) Ctx(ctx context.Context) context.Context {
 	client := ctx.Value(solarisClnt)
 	if client == nil {
 		ctx = context.WithValue(ctx, solarisClnt, r.svc)
+		for name, mv := range r.metrics {
+			ctx = runner.WithMetric(ctx, name, mv)
+		}
 	}
 	return ctx
 }
 
+// workerAffinityKey returns the worker the request is made by, the
+// requests out of the workers are spread round-robin
+func workerAffinityKey(ctx context.Context) string {
+	if worker := runner.GetWorker(ctx); worker > 0 {
+		return strconv.FormatInt(worker, 10)
+	}
+	return ""
+}
+
 func (r *connectScenarioResult) Error() error {
 	return nil
 }
@@ -0,0 +1,57 @@
+package solaris
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solarisdb/perftests/pkg/inmem"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConnectInMem(t *testing.T) {
+	logger := logging.NewLogger("test")
+	ctx := context.Background()
+
+	res := <-NewConnect(&connectExecutor{name: ConnectName, Logger: logger}, "test").
+		RunScenario(ctx, model.ToScenarioConfig(&ConnectCfg{Address: "inmem://test-connect"}))
+	assert.NoError(t, res.Error())
+	ctx = res.Ctx(ctx)
+
+	res = <-NewCreateLog(&createLogExecutor{name: CreateLogName, Logger: logger}, "test").
+		RunScenario(ctx, model.ToScenarioConfig(&CreateLogCfg{Tags: map[string]string{"test": "connect"}}))
+	assert.NoError(t, res.Error())
+	ctx = res.Ctx(ctx)
+
+	res = <-NewAppendMsg(&appendMsgExecutor{name: AppendRunName, Logger: logger}, "test").
+		RunScenario(ctx, model.ToScenarioConfig(&AppendCfg{MessageSize: 16, BatchSize: 10, Number: 3}))
+	assert.NoError(t, res.Error())
+
+	clnt := ctx.Value(solarisClnt).(solaris.ServiceClient)
+	cr, err := clnt.CountRecords(ctx, &solaris.QueryRecordsRequest{LogsCondition: "tag('test') = 'connect'"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(30), cr.Total)
+
+	// the injected errors fail the calls
+	res = <-NewConnect(&connectExecutor{name: ConnectName, Logger: logger}, "test").
+		RunScenario(ctx, model.ToScenarioConfig(&ConnectCfg{Address: "inmem://test-connect", InMem: &inmem.Config{ErrorRate: 1}}))
+	assert.NoError(t, res.Error())
+	// the nested connect keeps the client of the outer one
+	assert.Equal(t, clnt, res.Ctx(ctx).Value(solarisClnt))
+	_, err = clnt.CountRecords(ctx, &solaris.QueryRecordsRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestWorkerAffinityKey(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, workerAffinityKey(ctx))
+	w1, w2 := runner.WithWorker(ctx), runner.WithWorker(ctx)
+	assert.NotEmpty(t, workerAffinityKey(w1))
+	assert.NotEqual(t, workerAffinityKey(w1), workerAffinityKey(w2))
+	assert.Equal(t, workerAffinityKey(w1), workerAffinityKey(context.WithValue(w1, solarisLog, "log")))
+}
@@ -25,6 +25,12 @@ type (
 
 	CreateLogCfg struct {
 		Tags map[string]string `yaml:"tags" json:"tags"`
+		// LogSet is the name of the log set (see LogSetCfg) the created log
+		// is added to instead of the context log, the set is created if
+		// it does not exist
+		LogSet string `yaml:"logSet,omitempty" json:"logSet,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
 	}
 
 	createLogScenarioResult struct {
@@ -85,13 +91,23 @@ func (r *createLog) run(ctx context.Context, config *model.ScenarioConfig) (done
 		return
 	}
 
-	log, err := clnt.CreateLog(ctx, &solaris.Log{
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
+	log, err := call(ctx, policy, clnt.CreateLog, &solaris.Log{
 		Tags: cfg.Tags,
 	})
 	if err != nil {
 		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to create a log"))
 		return
 	}
+	if len(cfg.LogSet) > 0 {
+		doneCh <- addToLogSet(ctx, cfg.LogSet, log.ID)
+		return
+	}
 	doneCh <- &createLogScenarioResult{
 		log.ID,
 	}
@@ -0,0 +1,137 @@
+package solaris
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	createLogs struct {
+		exec *createLogsExecutor
+		name string
+	}
+
+	createLogsExecutor struct {
+		name     string
+		Registry *runner.Registry `inject:""`
+		Logger   logging.Logger   `inject:""`
+	}
+
+	// CreateLogsCfg describes the sequential creation of Number logs with
+	// the generated tags, the created logs are added to the LogSet if it
+	// is set (see CreateLogCfg), otherwise they should be deleted by the
+	// tags condition (see DeleteLogCfg)
+	CreateLogsCfg struct {
+		Number             int     `yaml:"number" json:"number"`
+		Tags               TagsCfg `yaml:"tags" json:"tags"`
+		LogSet             string  `yaml:"logSet,omitempty" json:"logSet,omitempty"`
+		TimeoutMetricName  string  `yaml:"timeoutMetricName,omitempty" json:"timeoutMetricName,omitempty"`
+		LatencyMetricName  string  `yaml:"latencyMetricName,omitempty" json:"latencyMetricName,omitempty"`
+		LogsRateMetricName string  `yaml:"logsRateMetricName,omitempty" json:"logsRateMetricName,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
+	}
+)
+
+const CreateLogsName = "solaris.createLogs"
+
+func NewCreateLogs(exec *createLogsExecutor, prefix string) runner.ScenarioRunner {
+	return &createLogs{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), runner.GetRunnerIndex())}
+}
+
+func NewCreateLogsExecutor() runner.ScenarioExecutor {
+	return &createLogsExecutor{name: CreateLogsName}
+}
+
+func (r *createLogsExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *createLogsExecutor) Name() string {
+	return r.name
+}
+
+func (r *createLogsExecutor) New(prefix string) runner.ScenarioRunner {
+	return NewCreateLogs(r, prefix)
+}
+
+func (r *createLogs) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan runner.ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *createLogs) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan runner.ScenarioResult) {
+	doneCh = make(chan runner.ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed))
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[CreateLogsCfg](config)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse scenario config %w", err))
+		return
+	}
+	if cfg.Number == 0 {
+		cfg.Number = 1
+	}
+	tags, err := newTagsGenerator(cfg.Tags)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, err)
+		return
+	}
+
+	clnt, _ := ctx.Value(solarisClnt).(solaris.ServiceClient)
+	if clnt == nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris service not found"))
+		return
+	}
+
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
+	toMetric, _ := runner.GetDurationMetric(ctx, cfg.TimeoutMetricName)
+	latencyMetric, _ := runner.GetHistogramMetric(ctx, cfg.LatencyMetricName)
+	logsInSecMetric, _ := runner.GetRateMetric(ctx, cfg.LogsRateMetricName)
+	ids := make([]string, 0, cfg.Number)
+	for i := 0; i < cfg.Number; i++ {
+		log, dur, err := timedCall(ctx, policy, clnt.CreateLog, &solaris.Log{Tags: tags()})
+		if err != nil {
+			if policy.tolerate(ctx, err) {
+				continue
+			}
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to create log: %w", err))
+			return
+		}
+		if toMetric != nil {
+			toMetric.Add(dur.Nanoseconds())
+		}
+		if latencyMetric != nil {
+			latencyMetric.Add(dur.Nanoseconds())
+		}
+		if logsInSecMetric != nil {
+			logsInSecMetric.Add(1, dur)
+		}
+		ids = append(ids, log.ID)
+	}
+
+	if len(cfg.LogSet) > 0 {
+		doneCh <- addToLogSet(ctx, cfg.LogSet, ids...)
+		return
+	}
+	doneCh <- runner.NewStaticScenarioResult(ctx, nil)
+	return
+}
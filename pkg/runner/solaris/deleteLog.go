@@ -23,8 +23,20 @@ type (
 		Logger   logging.Logger   `inject:""`
 	}
 
+	// DeleteLogCfg is optional, the log of the context is deleted if the
+	// Condition and the LogSet are empty. Otherwise, all the logs matching
+	// the condition or all the logs of the set are deleted by one request
+	// and the log of the context is kept.
+	DeleteLogCfg struct {
+		Condition string `yaml:"condition,omitempty" json:"condition,omitempty"`
+		LogSet    string `yaml:"logSet,omitempty" json:"logSet,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
+	}
+
 	deleteLogScenarioResult struct {
-		logID string
+		logID       string
+		byCondition bool
 	}
 )
 
@@ -59,7 +71,7 @@ func (r *deleteLog) RunScenario(ctx context.Context, config *model.ScenarioConfi
 	return r.run(ctx, config)
 }
 
-func (r *deleteLog) run(ctx context.Context, _ *model.ScenarioConfig) (doneCh chan runner.ScenarioResult) {
+func (r *deleteLog) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan runner.ScenarioResult) {
 	doneCh = make(chan runner.ScenarioResult, 1)
 	defer close(doneCh)
 
@@ -68,17 +80,63 @@ func (r *deleteLog) run(ctx context.Context, _ *model.ScenarioConfig) (doneCh ch
 		return
 	}
 
+	var cfg DeleteLogCfg
+	if config != nil && len(config.RawCfg) > 0 {
+		var err error
+		if cfg, err = model.FromScenarioConfig[DeleteLogCfg](config); err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse scenario config %w", err))
+			return
+		}
+	}
+
 	clnt, _ := ctx.Value(solarisClnt).(solaris.ServiceClient)
 	if clnt == nil {
 		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris service not found"))
 		return
 	}
+
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
+	var set *logSet
+	var ids []string
+	if len(cfg.LogSet) > 0 {
+		var ok bool
+		if set, ok = getLogSet(ctx, cfg.LogSet); !ok {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("log set %s not found: %w", cfg.LogSet, errors.ErrNotExist))
+			return
+		}
+		ids = set.sample(0)
+		if len(ids) == 0 {
+			doneCh <- &deleteLogScenarioResult{byCondition: true}
+			return
+		}
+		cfg.Condition = logIDsCondition(ids)
+	}
+	if len(cfg.Condition) > 0 {
+		res, err := call(ctx, policy, clnt.DeleteLogs, &solaris.DeleteLogsRequest{Condition: cfg.Condition})
+		if err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to delete logs by condition %q: %w", cfg.Condition, err))
+			return
+		}
+		// the logs are removed from the set only when deleted, so
+		// the set may be deleted again if the call failed
+		if set != nil {
+			set.remove(ids...)
+		}
+		r.exec.Logger.Infof("%s: %d logs deleted", r.name, len(res.DeletedIDs))
+		doneCh <- &deleteLogScenarioResult{byCondition: true}
+		return
+	}
 	log, _ := ctx.Value(solarisLog).(string)
 	if len(log) == 0 {
 		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris log not found"))
 		return
 	}
-	_, err := clnt.DeleteLogs(ctx, &solaris.DeleteLogsRequest{
+	_, err = call(ctx, policy, clnt.DeleteLogs, &solaris.DeleteLogsRequest{
 		Condition: fmt.Sprintf("logID='%s'", log),
 	})
 	if err != nil {
@@ -90,6 +148,9 @@ func (r *deleteLog) run(ctx context.Context, _ *model.ScenarioConfig) (doneCh ch
 }
 
 func (r *deleteLogScenarioResult) Ctx(ctx context.Context) context.Context {
+	if r.byCondition {
+		return ctx
+	}
 	return context.WithValue(ctx, solarisLog, nil)
 }
 
@@ -0,0 +1,193 @@
+package solaris
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/golibs/container"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	// logSet is the named set of the log IDs kept in the context, the
+	// solaris.createLog and solaris.createLogs scenarios add the created
+	// logs to the set, so the set may be queried or deleted at once
+	logSet struct {
+		lock sync.Mutex
+		ids  []string
+		next int
+	}
+
+	newLogSet struct {
+		exec *newLogSetExecutor
+		name string
+	}
+
+	newLogSetExecutor struct {
+		name     string
+		Registry *runner.Registry `inject:""`
+		Logger   logging.Logger   `inject:""`
+	}
+
+	// LogSetCfg describes the empty log set added to the context, the set
+	// must be created before the logs are added to it concurrently
+	LogSetCfg struct {
+		Name string `yaml:"name" json:"name"`
+	}
+
+	logSetScenarioResult struct {
+		name string
+		set  *logSet
+	}
+)
+
+const LogSetName = "solaris.logSet"
+
+func NewLogSet(exec *newLogSetExecutor, prefix string) runner.ScenarioRunner {
+	return &newLogSet{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), runner.GetRunnerIndex())}
+}
+
+func NewLogSetExecutor() runner.ScenarioExecutor {
+	return &newLogSetExecutor{name: LogSetName}
+}
+
+func (r *newLogSetExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *newLogSetExecutor) Name() string {
+	return r.name
+}
+
+func (r *newLogSetExecutor) New(prefix string) runner.ScenarioRunner {
+	return NewLogSet(r, prefix)
+}
+
+func (r *newLogSet) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan runner.ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *newLogSet) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan runner.ScenarioResult) {
+	doneCh = make(chan runner.ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed))
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[LogSetCfg](config)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse scenario config %w", err))
+		return
+	}
+	if len(cfg.Name) == 0 {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("log set name is empty: %w", errors.ErrInvalid))
+		return
+	}
+	doneCh <- &logSetScenarioResult{name: cfg.Name, set: new(logSet)}
+	return
+}
+
+func (r *logSetScenarioResult) Ctx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, r.name, r.set)
+}
+
+func (r *logSetScenarioResult) Error() error {
+	return nil
+}
+
+// getLogSet returns the log set of the context
+func getLogSet(ctx context.Context, name string) (*logSet, bool) {
+	set, ok := ctx.Value(name).(*logSet)
+	return set, ok && set != nil
+}
+
+// addToLogSet adds the logs to the set of the context, the returned result
+// adds the new set to the context if the set does not exist
+func addToLogSet(ctx context.Context, name string, ids ...string) runner.ScenarioResult {
+	if set, ok := getLogSet(ctx, name); ok {
+		set.add(ids...)
+		return runner.NewStaticScenarioResult(ctx, nil)
+	}
+	set := new(logSet)
+	set.add(ids...)
+	return &logSetScenarioResult{name: name, set: set}
+}
+
+func (s *logSet) add(ids ...string) {
+	s.lock.Lock()
+	s.ids = append(s.ids, ids...)
+	s.lock.Unlock()
+}
+
+// sample returns n random log IDs of the set, or all of them if n is not
+// positive or not less than the set size
+func (s *logSet) sample(n int) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if n <= 0 || n >= len(s.ids) {
+		return container.SliceCopy(s.ids)
+	}
+	res := make([]string, 0, n)
+	for _, i := range rand.Perm(len(s.ids))[:n] {
+		res = append(res, s.ids[i])
+	}
+	return res
+}
+
+// pick returns the next log of the set round-robin, or false if the set is empty
+func (s *logSet) pick() (string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.ids) == 0 {
+		return "", false
+	}
+	id := s.ids[s.next%len(s.ids)]
+	s.next++
+	return id, true
+}
+
+// remove removes the logs from the set, the logs added after
+// the removed ones were sampled are kept
+func (s *logSet) remove(ids ...string) {
+	removed := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		removed[id] = struct{}{}
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	kept := s.ids[:0]
+	for _, id := range s.ids {
+		if _, ok := removed[id]; !ok {
+			kept = append(kept, id)
+		}
+	}
+	clear(s.ids[len(kept):])
+	s.ids = kept
+}
+
+// logIDsCondition returns the logs condition matching the log IDs
+func logIDsCondition(ids []string) string {
+	var sb strings.Builder
+	sb.WriteString("logID IN [")
+	for i, id := range ids {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("'")
+		sb.WriteString(id)
+		sb.WriteString("'")
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
@@ -0,0 +1,242 @@
+package solaris
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/payload"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+
+	"context"
+)
+
+type (
+	queryFiltered struct {
+		exec *queryFilteredExecutor
+		name string
+	}
+
+	queryFilteredExecutor struct {
+		name     string
+		Registry *runner.Registry `inject:""`
+		Logger   logging.Logger   `inject:""`
+	}
+
+	// QueryFilteredCfg describes Number queries of the records matching the
+	// condition, the condition of every query is produced from the template
+	// randomly chosen from the Conditions (see payload.NewTemplate for the
+	// placeholders), e.g. "ctime > '2024-01-01' AND payload LIKE '%{{string(2)}}%'".
+	// The records are queried from the LogIDs, or from the LogSetSample
+	// random logs of the LogSet (all the logs of the set if the sample is
+	// zero), or from the logs matching the LogsCondition, or from the log of
+	// the context otherwise. Every query reads up to Pages pages (one by
+	// default, -1 means all the pages).
+	QueryFilteredCfg struct {
+		Conditions    []string `yaml:"conditions,omitempty" json:"conditions,omitempty"`
+		LogIDs        []string `yaml:"logIDs,omitempty" json:"logIDs,omitempty"`
+		LogSet        string   `yaml:"logSet,omitempty" json:"logSet,omitempty"`
+		LogSetSample  int      `yaml:"logSetSample,omitempty" json:"logSetSample,omitempty"`
+		LogsCondition string   `yaml:"logsCondition,omitempty" json:"logsCondition,omitempty"`
+		Descending    bool     `yaml:"descending,omitempty" json:"descending,omitempty"`
+		Step          int64    `yaml:"step" json:"step"`
+		Pages         int      `yaml:"pages,omitempty" json:"pages,omitempty"`
+		Number        int      `yaml:"number" json:"number"`
+		// ReturnedMetricName is the INT metric of the returned records
+		ReturnedMetricName string `yaml:"returnedMetricName,omitempty" json:"returnedMetricName,omitempty"`
+		// MatchedMetricName and ScannedMetricName are the INT metrics of the
+		// records matching the condition and of all the records of the queried
+		// logs, they are counted by the additional CountRecords request
+		// which is not included in the latency
+		MatchedMetricName   string `yaml:"matchedMetricName,omitempty" json:"matchedMetricName,omitempty"`
+		ScannedMetricName   string `yaml:"scannedMetricName,omitempty" json:"scannedMetricName,omitempty"`
+		TimeoutMetricName   string `yaml:"timeoutMetricName,omitempty" json:"timeoutMetricName,omitempty"`
+		LatencyMetricName   string `yaml:"latencyMetricName,omitempty" json:"latencyMetricName,omitempty"`
+		MsgsRateMetricName  string `yaml:"msgsRateMetricName,omitempty" json:"msgsRateMetricName,omitempty"`
+		BytesRateMetricName string `yaml:"bytesRateMetricName,omitempty" json:"bytesRateMetricName,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
+	}
+)
+
+const QueryFilteredRunName = "solaris.queryFiltered"
+
+func NewQueryFiltered(exec *queryFilteredExecutor, prefix string) runner.ScenarioRunner {
+	return &queryFiltered{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), runner.GetRunnerIndex())}
+}
+
+func NewQueryFilteredExecutor() runner.ScenarioExecutor {
+	return &queryFilteredExecutor{name: QueryFilteredRunName}
+}
+
+func (r *queryFilteredExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *queryFilteredExecutor) Name() string {
+	return r.name
+}
+
+func (r *queryFilteredExecutor) New(prefix string) runner.ScenarioRunner {
+	return NewQueryFiltered(r, prefix)
+}
+
+func (r *queryFiltered) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan runner.ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *queryFiltered) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan runner.ScenarioResult) {
+	doneCh = make(chan runner.ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed))
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[QueryFilteredCfg](config)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse scenario config %w", err))
+		return
+	}
+	if cfg.Step == 0 {
+		cfg.Step = defaultQueryRecordsLimit
+	}
+	if cfg.Number == 0 {
+		cfg.Number = 1
+	}
+	if cfg.Pages == 0 {
+		cfg.Pages = 1
+	}
+	var conditions []payload.Generator
+	for _, cond := range cfg.Conditions {
+		gen, err := payload.NewTemplate(cond)
+		if err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse condition %q: %w", cond, err))
+			return
+		}
+		conditions = append(conditions, gen)
+	}
+	clnt, _ := ctx.Value(solarisClnt).(solaris.ServiceClient)
+	if clnt == nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris service not found"))
+		return
+	}
+
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
+	logIDs := cfg.LogIDs
+	var set *logSet
+	if len(logIDs) == 0 && len(cfg.LogSet) > 0 {
+		var ok bool
+		if set, ok = getLogSet(ctx, cfg.LogSet); !ok {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("log set %s not found: %w", cfg.LogSet, errors.ErrNotExist))
+			return
+		}
+	} else if len(logIDs) == 0 && len(cfg.LogsCondition) == 0 {
+		log, _ := ctx.Value(solarisLog).(string)
+		if len(log) == 0 {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris log not found"))
+			return
+		}
+		logIDs = []string{log}
+	}
+
+	toMetric, _ := runner.GetDurationMetric(ctx, cfg.TimeoutMetricName)
+	latencyMetric, _ := runner.GetHistogramMetric(ctx, cfg.LatencyMetricName)
+	bytesInSecMetric, _ := runner.GetRateMetric(ctx, cfg.BytesRateMetricName)
+	msgsInSecMetric, _ := runner.GetRateMetric(ctx, cfg.MsgsRateMetricName)
+	returnedMetric, _ := runner.GetIntMetric(ctx, cfg.ReturnedMetricName)
+	matchedMetric, _ := runner.GetIntMetric(ctx, cfg.MatchedMetricName)
+	scannedMetric, _ := runner.GetIntMetric(ctx, cfg.ScannedMetricName)
+
+	for i := 0; i < cfg.Number; i++ {
+		var condition string
+		if len(conditions) > 0 {
+			condition = string(conditions[rand.Intn(len(conditions))].Next())
+		}
+		if set != nil {
+			if logIDs = set.sample(cfg.LogSetSample); len(logIDs) == 0 {
+				doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("log set %s is empty: %w", cfg.LogSet, errors.ErrInvalid))
+				return
+			}
+		}
+		fromID := ""
+		for page := 0; cfg.Pages == -1 || page < cfg.Pages; page++ {
+			req := &solaris.QueryRecordsRequest{
+				LogsCondition: cfg.LogsCondition,
+				Condition:     condition,
+				LogIDs:        logIDs,
+				Descending:    cfg.Descending,
+				StartRecordID: fromID,
+				Limit:         cfg.Step,
+			}
+			res, dur, err := timedCall(ctx, policy, clnt.QueryRecords, req)
+			if err != nil {
+				if policy.tolerate(ctx, err) {
+					// the page walk of the failed query stops, the next query is made
+					break
+				}
+				doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to query records by condition %q: %w", condition, err))
+				return
+			}
+			if toMetric != nil {
+				toMetric.Add(dur.Nanoseconds())
+			}
+			if latencyMetric != nil {
+				latencyMetric.Add(dur.Nanoseconds())
+			}
+			if msgsInSecMetric != nil {
+				msgsInSecMetric.Add(float64(len(res.Records)), dur)
+			}
+			if bytesInSecMetric != nil {
+				var size int
+				for _, rec := range res.Records {
+					size += len(rec.Payload)
+				}
+				bytesInSecMetric.Add(float64(size), dur)
+			}
+			if returnedMetric != nil {
+				returnedMetric.Add(int64(len(res.Records)))
+			}
+			fromID = res.NextPageID
+			if fromID == "" {
+				break
+			}
+		}
+		if matchedMetric != nil || scannedMetric != nil {
+			res, err := call(ctx, policy, clnt.CountRecords, &solaris.QueryRecordsRequest{
+				LogsCondition: cfg.LogsCondition,
+				Condition:     condition,
+				LogIDs:        logIDs,
+			})
+			if err != nil {
+				if policy.tolerate(ctx, err) {
+					// the records of the failed count are not added to the matched and scanned metrics
+					continue
+				}
+				doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to count records by condition %q: %w", condition, err))
+				return
+			}
+			if matchedMetric != nil {
+				matchedMetric.Add(res.Count)
+			}
+			if scannedMetric != nil {
+				scannedMetric.Add(res.Total)
+			}
+		}
+	}
+
+	doneCh <- runner.NewStaticScenarioResult(ctx, nil)
+	return
+}
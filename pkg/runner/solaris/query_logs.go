@@ -0,0 +1,167 @@
+package solaris
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/payload"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	queryLogs struct {
+		exec *queryLogsExecutor
+		name string
+	}
+
+	queryLogsExecutor struct {
+		name     string
+		Registry *runner.Registry `inject:""`
+		Logger   logging.Logger   `inject:""`
+	}
+
+	// QueryLogsCfg describes Number queries of the logs matching the tags
+	// condition, the condition of every query is produced from the template
+	// randomly chosen from the Conditions (see payload.NewTemplate for the
+	// placeholders), e.g. "tag('tag0') = 'val{{int(0, 99)}}'". Every query
+	// reads up to Pages pages (one by default, -1 means all the pages).
+	QueryLogsCfg struct {
+		Conditions []string `yaml:"conditions,omitempty" json:"conditions,omitempty"`
+		Step       int64    `yaml:"step" json:"step"`
+		Pages      int      `yaml:"pages,omitempty" json:"pages,omitempty"`
+		Number     int      `yaml:"number" json:"number"`
+		// ReturnedMetricName is the INT metric of the returned logs
+		ReturnedMetricName string `yaml:"returnedMetricName,omitempty" json:"returnedMetricName,omitempty"`
+		TimeoutMetricName  string `yaml:"timeoutMetricName,omitempty" json:"timeoutMetricName,omitempty"`
+		LatencyMetricName  string `yaml:"latencyMetricName,omitempty" json:"latencyMetricName,omitempty"`
+		LogsRateMetricName string `yaml:"logsRateMetricName,omitempty" json:"logsRateMetricName,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
+	}
+)
+
+const QueryLogsName = "solaris.queryLogs"
+
+func NewQueryLogs(exec *queryLogsExecutor, prefix string) runner.ScenarioRunner {
+	return &queryLogs{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), runner.GetRunnerIndex())}
+}
+
+func NewQueryLogsExecutor() runner.ScenarioExecutor {
+	return &queryLogsExecutor{name: QueryLogsName}
+}
+
+func (r *queryLogsExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *queryLogsExecutor) Name() string {
+	return r.name
+}
+
+func (r *queryLogsExecutor) New(prefix string) runner.ScenarioRunner {
+	return NewQueryLogs(r, prefix)
+}
+
+func (r *queryLogs) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan runner.ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *queryLogs) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan runner.ScenarioResult) {
+	doneCh = make(chan runner.ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed))
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[QueryLogsCfg](config)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse scenario config %w", err))
+		return
+	}
+	if cfg.Step == 0 {
+		cfg.Step = defaultQueryRecordsLimit
+	}
+	if cfg.Number == 0 {
+		cfg.Number = 1
+	}
+	if cfg.Pages == 0 {
+		cfg.Pages = 1
+	}
+	var conditions []payload.Generator
+	for _, cond := range cfg.Conditions {
+		gen, err := payload.NewTemplate(cond)
+		if err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse condition %q: %w", cond, err))
+			return
+		}
+		conditions = append(conditions, gen)
+	}
+
+	clnt, _ := ctx.Value(solarisClnt).(solaris.ServiceClient)
+	if clnt == nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris service not found"))
+		return
+	}
+
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
+	toMetric, _ := runner.GetDurationMetric(ctx, cfg.TimeoutMetricName)
+	latencyMetric, _ := runner.GetHistogramMetric(ctx, cfg.LatencyMetricName)
+	logsInSecMetric, _ := runner.GetRateMetric(ctx, cfg.LogsRateMetricName)
+	returnedMetric, _ := runner.GetIntMetric(ctx, cfg.ReturnedMetricName)
+	for i := 0; i < cfg.Number; i++ {
+		var condition string
+		if len(conditions) > 0 {
+			condition = string(conditions[rand.Intn(len(conditions))].Next())
+		}
+		pageID := ""
+		for page := 0; cfg.Pages == -1 || page < cfg.Pages; page++ {
+			res, dur, err := timedCall(ctx, policy, clnt.QueryLogs, &solaris.QueryLogsRequest{
+				Condition: condition,
+				PageID:    pageID,
+				Limit:     cfg.Step,
+			})
+			if err != nil {
+				if policy.tolerate(ctx, err) {
+					// the page walk of the failed query stops, the next query is made
+					break
+				}
+				doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to query logs by condition %q: %w", condition, err))
+				return
+			}
+			if toMetric != nil {
+				toMetric.Add(dur.Nanoseconds())
+			}
+			if latencyMetric != nil {
+				latencyMetric.Add(dur.Nanoseconds())
+			}
+			if logsInSecMetric != nil {
+				logsInSecMetric.Add(float64(len(res.Logs)), dur)
+			}
+			if returnedMetric != nil {
+				returnedMetric.Add(int64(len(res.Logs)))
+			}
+			pageID = res.NextPageID
+			if pageID == "" {
+				break
+			}
+		}
+	}
+
+	doneCh <- runner.NewStaticScenarioResult(ctx, nil)
+	return
+}
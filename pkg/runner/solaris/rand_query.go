@@ -33,8 +33,11 @@ type (
 		Step                int64  `yaml:"step" json:"step"`
 		Number              int    `yaml:"number" json:"number"`
 		TimeoutMetricName   string `yaml:"timeoutMetricName,omitempty" json:"timeoutMetricName,omitempty"`
+		LatencyMetricName   string `yaml:"latencyMetricName,omitempty" json:"latencyMetricName,omitempty"`
 		MsgsRateMetricName  string `yaml:"msgsRateMetricName,omitempty" json:"msgsRateMetricName,omitempty"`
 		BytesRateMetricName string `yaml:"bytesRateMetricName,omitempty" json:"bytesRateMetricName,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
 	}
 )
 
@@ -94,6 +97,12 @@ func (r *randQueryMsgs) run(ctx context.Context, config *model.ScenarioConfig) (
 		return
 	}
 
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
 	log, _ := ctx.Value(solarisLog).(string)
 	if len(log) == 0 {
 		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris log not found"))
@@ -101,6 +110,7 @@ func (r *randQueryMsgs) run(ctx context.Context, config *model.ScenarioConfig) (
 	}
 
 	toMetric, _ := runner.GetDurationMetric(ctx, cfg.TimeoutMetricName)
+	latencyMetric, _ := runner.GetHistogramMetric(ctx, cfg.LatencyMetricName)
 	bytesInSecMetric, _ := runner.GetRateMetric(ctx, cfg.BytesRateMetricName)
 	msgsInSecMetric, _ := runner.GetRateMetric(ctx, cfg.MsgsRateMetricName)
 
@@ -109,7 +119,7 @@ func (r *randQueryMsgs) run(ctx context.Context, config *model.ScenarioConfig) (
 		Limit:         1,
 		StartRecordID: "",
 	}
-	res, err := clnt.QueryRecords(ctx, req)
+	res, err := call(ctx, policy, clnt.QueryRecords, req)
 	if err != nil || len(res.Records) == 0 {
 		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to read first record: %w", err))
 		return
@@ -122,7 +132,7 @@ func (r *randQueryMsgs) run(ctx context.Context, config *model.ScenarioConfig) (
 		StartRecordID: maxID,
 		Descending:    true,
 	}
-	res, err = clnt.QueryRecords(ctx, req)
+	res, err = call(ctx, policy, clnt.QueryRecords, req)
 	if err != nil || len(res.Records) == 0 {
 		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to read last record: %w", err))
 		return
@@ -145,16 +155,22 @@ func (r *randQueryMsgs) run(ctx context.Context, config *model.ScenarioConfig) (
 			Limit:         cfg.Step,
 			StartRecordID: nextID,
 		}
-		start := time.Now()
-		res, err = clnt.QueryRecords(ctx, req)
+		var dur time.Duration
+		res, dur, err = timedCall(ctx, policy, clnt.QueryRecords, req)
 		if err != nil {
+			if policy.tolerate(ctx, err) {
+				i++
+				continue
+			}
 			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to query records: %w", err))
 			return
 		}
-		dur := time.Since(start)
 		if toMetric != nil {
 			toMetric.Add(dur.Nanoseconds())
 		}
+		if latencyMetric != nil {
+			latencyMetric.Add(dur.Nanoseconds())
+		}
 		if msgsInSecMetric != nil {
 			msgsInSecMetric.Add(float64(len(res.Records)), dur)
 		}
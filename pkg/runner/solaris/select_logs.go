@@ -0,0 +1,157 @@
+package solaris
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	selectLogs struct {
+		exec *selectLogsExecutor
+		name string
+	}
+
+	selectLogsExecutor struct {
+		name     string
+		Registry *runner.Registry `inject:""`
+		Logger   logging.Logger   `inject:""`
+	}
+
+	// SelectLogsCfg describes the selection of the existing logs, so the
+	// logs filled by the previous runs can be read again. If FromLogSet is
+	// set, the next log of the set (round-robin) becomes the context log.
+	// Otherwise, up to Number logs (all if zero) matching the Condition are
+	// added to the LogSet, or the first of them becomes the context log if
+	// the LogSet is empty. The run fails if no log is selected.
+	SelectLogsCfg struct {
+		Condition  string `yaml:"condition,omitempty" json:"condition,omitempty"`
+		Number     int    `yaml:"number,omitempty" json:"number,omitempty"`
+		LogSet     string `yaml:"logSet,omitempty" json:"logSet,omitempty"`
+		FromLogSet string `yaml:"fromLogSet,omitempty" json:"fromLogSet,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
+	}
+)
+
+const (
+	SelectLogsName = "solaris.selectLogs"
+	// selectLogsStep is the number of the logs queried by one request
+	selectLogsStep = 1000
+)
+
+func NewSelectLogs(exec *selectLogsExecutor, prefix string) runner.ScenarioRunner {
+	return &selectLogs{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), runner.GetRunnerIndex())}
+}
+
+func NewSelectLogsExecutor() runner.ScenarioExecutor {
+	return &selectLogsExecutor{name: SelectLogsName}
+}
+
+func (r *selectLogsExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *selectLogsExecutor) Name() string {
+	return r.name
+}
+
+func (r *selectLogsExecutor) New(prefix string) runner.ScenarioRunner {
+	return NewSelectLogs(r, prefix)
+}
+
+func (r *selectLogs) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan runner.ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *selectLogs) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan runner.ScenarioResult) {
+	doneCh = make(chan runner.ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed))
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[SelectLogsCfg](config)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse scenario config %w", err))
+		return
+	}
+
+	if len(cfg.FromLogSet) > 0 {
+		set, ok := getLogSet(ctx, cfg.FromLogSet)
+		if !ok {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("log set %s not found: %w", cfg.FromLogSet, errors.ErrNotExist))
+			return
+		}
+		logID, ok := set.pick()
+		if !ok {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("log set %s is empty: %w", cfg.FromLogSet, errors.ErrNotExist))
+			return
+		}
+		doneCh <- &createLogScenarioResult{logID}
+		return
+	}
+
+	clnt, _ := ctx.Value(solarisClnt).(solaris.ServiceClient)
+	if clnt == nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris service not found"))
+		return
+	}
+
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
+	number := cfg.Number
+	if len(cfg.LogSet) == 0 {
+		number = 1
+	}
+
+	var ids []string
+	pageID := ""
+	for number == 0 || len(ids) < number {
+		res, err := call(ctx, policy, clnt.QueryLogs, &solaris.QueryLogsRequest{
+			Condition: cfg.Condition,
+			PageID:    pageID,
+			Limit:     selectLogsStep,
+		})
+		if err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to query logs by condition %q: %w", cfg.Condition, err))
+			return
+		}
+		for _, log := range res.Logs {
+			if number > 0 && len(ids) >= number {
+				break
+			}
+			ids = append(ids, log.ID)
+		}
+		pageID = res.NextPageID
+		if pageID == "" {
+			break
+		}
+	}
+	if len(ids) == 0 {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("no logs found by condition %q: %w", cfg.Condition, errors.ErrNotExist))
+		return
+	}
+	r.exec.Logger.Infof("%s: %d logs selected by condition %q", r.name, len(ids), cfg.Condition)
+
+	if len(cfg.LogSet) > 0 {
+		doneCh <- addToLogSet(ctx, cfg.LogSet, ids...)
+		return
+	}
+	doneCh <- &createLogScenarioResult{ids[0]}
+	return
+}
@@ -2,7 +2,6 @@ package solaris
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/solarisdb/perftests/pkg/model"
 	"github.com/solarisdb/perftests/pkg/runner"
@@ -30,8 +29,11 @@ type (
 		Step                int64  `yaml:"step" json:"step"`
 		Number              int    `yaml:"number" json:"number"`
 		TimeoutMetricName   string `yaml:"timeoutMetricName,omitempty" json:"timeoutMetricName,omitempty"`
+		LatencyMetricName   string `yaml:"latencyMetricName,omitempty" json:"latencyMetricName,omitempty"`
 		MsgsRateMetricName  string `yaml:"msgsRateMetricName,omitempty" json:"msgsRateMetricName,omitempty"`
 		BytesRateMetricName string `yaml:"bytesRateMetricName,omitempty" json:"bytesRateMetricName,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
 	}
 )
 
@@ -93,6 +95,12 @@ func (r *seqQueryMsgs) run(ctx context.Context, config *model.ScenarioConfig) (d
 		return
 	}
 
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
 	log, _ := ctx.Value(solarisLog).(string)
 	if len(log) == 0 {
 		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris log not found"))
@@ -100,6 +108,7 @@ func (r *seqQueryMsgs) run(ctx context.Context, config *model.ScenarioConfig) (d
 	}
 
 	toMetric, _ := runner.GetDurationMetric(ctx, cfg.TimeoutMetricName)
+	latencyMetric, _ := runner.GetHistogramMetric(ctx, cfg.LatencyMetricName)
 	bytesInSecMetric, _ := runner.GetRateMetric(ctx, cfg.BytesRateMetricName)
 	msgsInSecMetric, _ := runner.GetRateMetric(ctx, cfg.MsgsRateMetricName)
 
@@ -114,16 +123,21 @@ func (r *seqQueryMsgs) run(ctx context.Context, config *model.ScenarioConfig) (d
 			Limit:         cfg.Step,
 			StartRecordID: fromID,
 		}
-		start := time.Now()
-		res, err := clnt.QueryRecords(ctx, req)
+		res, dur, err := timedCall(ctx, policy, clnt.QueryRecords, req)
 		if err != nil {
+			if policy.tolerate(ctx, err) {
+				i++
+				continue
+			}
 			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to query records: %w", err))
 			return
 		}
-		dur := time.Since(start)
 		if toMetric != nil {
 			toMetric.Add(dur.Nanoseconds())
 		}
+		if latencyMetric != nil {
+			latencyMetric.Add(dur.Nanoseconds())
+		}
 		if msgsInSecMetric != nil {
 			msgsInSecMetric.Add(float64(len(res.Records)), dur)
 		}
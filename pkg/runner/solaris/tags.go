@@ -0,0 +1,49 @@
+package solaris
+
+import (
+	"fmt"
+
+	"github.com/solarisdb/perftests/pkg/utils"
+)
+
+// TagsCfg describes the generated log tags: Count tags named tag0, tag1...
+// with the values val0, val1... up to the Cardinality distinct values per
+// tag. The value index is drawn from the Distribution (see
+// utils.ParseDistribution), "uniform(0, Cardinality)" by default, and it is
+// wrapped around the Cardinality. The Static tags are added as is.
+type TagsCfg struct {
+	Static       map[string]string `yaml:"static,omitempty" json:"static,omitempty"`
+	Count        int               `yaml:"count,omitempty" json:"count,omitempty"`
+	Cardinality  int               `yaml:"cardinality,omitempty" json:"cardinality,omitempty"`
+	Distribution string            `yaml:"distribution,omitempty" json:"distribution,omitempty"`
+}
+
+// newTagsGenerator returns the function generating the tag sets of the config
+func newTagsGenerator(cfg TagsCfg) (func() map[string]string, error) {
+	cardinality := max(cfg.Cardinality, 1)
+	distribution := cfg.Distribution
+	if len(distribution) == 0 {
+		distribution = fmt.Sprintf("uniform(0, %d)", cardinality)
+	}
+	var dist utils.Distribution
+	if cfg.Count > 0 {
+		var err error
+		if dist, err = utils.ParseDistribution(distribution); err != nil {
+			return nil, fmt.Errorf("failed to parse tags distribution: %w", err)
+		}
+	}
+	return func() map[string]string {
+		tags := make(map[string]string, len(cfg.Static)+cfg.Count)
+		for k, v := range cfg.Static {
+			tags[k] = v
+		}
+		for i := 0; i < cfg.Count; i++ {
+			idx := dist() % int64(cardinality)
+			if idx < 0 {
+				idx += int64(cardinality)
+			}
+			tags[fmt.Sprintf("tag%d", i)] = fmt.Sprintf("val%d", idx)
+		}
+		return tags
+	}, nil
+}
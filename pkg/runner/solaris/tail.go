@@ -0,0 +1,213 @@
+package solaris
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/payload"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/solarisdb/solaris/golibs/ulidutils"
+
+	"context"
+)
+
+type (
+	tailMsgs struct {
+		exec *tailMsgsExecutor
+		name string
+	}
+
+	tailMsgsExecutor struct {
+		name     string
+		Registry *runner.Registry `inject:""`
+		Logger   logging.Logger   `inject:""`
+	}
+
+	// TailMsgsCfg describes the consumer following the log from its end (or
+	// from the beginning if FromBeginning is set). The lag between the send
+	// time of the records appended with the stampTime option and the time
+	// they are read is added to the LagMetricName (DURATION or HISTOGRAM).
+	// The consumer stops when Number records are read or when the trigger
+	// is fired and all the appended records are read.
+	TailMsgsCfg struct {
+		Step          int64  `yaml:"step" json:"step"`
+		PollInterval  string `yaml:"pollInterval,omitempty" json:"pollInterval,omitempty"`
+		Number        int64  `yaml:"number,omitempty" json:"number,omitempty"`
+		TriggerName   string `yaml:"triggerName,omitempty" json:"triggerName,omitempty"`
+		FromBeginning bool   `yaml:"fromBeginning,omitempty" json:"fromBeginning,omitempty"`
+		// MaxLag fails the run if any record is read later than MaxLag after it is sent
+		MaxLag        string `yaml:"maxLag,omitempty" json:"maxLag,omitempty"`
+		LagMetricName string `yaml:"lagMetricName,omitempty" json:"lagMetricName,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
+	}
+)
+
+const (
+	TailMsgsRunName     = "solaris.tail"
+	defaultPollInterval = 100 * time.Millisecond
+)
+
+func NewTailMsgs(exec *tailMsgsExecutor, prefix string) runner.ScenarioRunner {
+	return &tailMsgs{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), runner.GetRunnerIndex())}
+}
+
+func NewTailMsgsExecutor() runner.ScenarioExecutor {
+	return &tailMsgsExecutor{name: TailMsgsRunName}
+}
+
+func (r *tailMsgsExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *tailMsgsExecutor) Name() string {
+	return r.name
+}
+
+func (r *tailMsgsExecutor) New(prefix string) runner.ScenarioRunner {
+	return NewTailMsgs(r, prefix)
+}
+
+func (r *tailMsgs) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan runner.ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *tailMsgs) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan runner.ScenarioResult) {
+	doneCh = make(chan runner.ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed))
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[TailMsgsCfg](config)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse scenario config %w", err))
+		return
+	}
+	if cfg.Step == 0 {
+		cfg.Step = defaultQueryRecordsLimit
+	}
+	pollInterval := defaultPollInterval
+	if len(cfg.PollInterval) > 0 {
+		if pollInterval, err = time.ParseDuration(cfg.PollInterval); err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse poll interval %w", err))
+			return
+		}
+	}
+	var maxLag time.Duration
+	if len(cfg.MaxLag) > 0 {
+		if maxLag, err = time.ParseDuration(cfg.MaxLag); err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse max lag %w", err))
+			return
+		}
+	}
+	var trigger context.Context
+	if len(cfg.TriggerName) > 0 {
+		if trigger, _ = ctx.Value(cfg.TriggerName).(context.Context); trigger == nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("trigger %s not found: %w", cfg.TriggerName, errors.ErrNotExist))
+			return
+		}
+	} else if cfg.Number <= 0 {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("either number or triggerName must be set: %w", errors.ErrInvalid))
+		return
+	}
+	clnt, _ := ctx.Value(solarisClnt).(solaris.ServiceClient)
+	if clnt == nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris service not found"))
+		return
+	}
+
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
+	log, _ := ctx.Value(solarisLog).(string)
+	if len(log) == 0 {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris log not found"))
+		return
+	}
+
+	durMetric, _ := runner.GetDurationMetric(ctx, cfg.LagMetricName)
+	histMetric, _ := runner.GetHistogramMetric(ctx, cfg.LagMetricName)
+
+	fromID := ""
+	if !cfg.FromBeginning {
+		res, err := call(ctx, policy, clnt.QueryRecords, &solaris.QueryRecordsRequest{LogIDs: []string{log}, Descending: true, Limit: 1})
+		if err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to query the last record: %w", err))
+			return
+		}
+		if len(res.Records) > 0 {
+			fromID = ulidutils.NextID(res.Records[0].ID)
+		}
+	}
+
+	var read int64
+	for {
+		// the records appended before the trigger is fired are read by the next query
+		fired := trigger != nil && trigger.Err() != nil
+		res, err := call(ctx, policy, clnt.QueryRecords, &solaris.QueryRecordsRequest{
+			LogIDs:        []string{log},
+			Limit:         cfg.Step,
+			StartRecordID: fromID,
+		})
+		if err != nil {
+			if policy.tolerate(ctx, err) {
+				continue
+			}
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to query records: %w", err))
+			return
+		}
+		now := time.Now()
+		for _, rec := range res.Records {
+			if sent, ok := payload.StampTime(rec.Payload); ok {
+				lag := now.Sub(sent)
+				if durMetric != nil {
+					durMetric.Add(lag.Nanoseconds())
+				}
+				if histMetric != nil {
+					histMetric.Add(lag.Nanoseconds())
+				}
+				if maxLag > 0 && lag > maxLag {
+					doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("record %s is read in %s, the max lag %s is exceeded: %w",
+						rec.ID, lag, maxLag, errors.ErrExhausted))
+					return
+				}
+			}
+			read++
+			if cfg.Number > 0 && read >= cfg.Number {
+				doneCh <- runner.NewStaticScenarioResult(ctx, nil)
+				return
+			}
+		}
+		if len(res.NextPageID) > 0 {
+			fromID = res.NextPageID
+			continue
+		}
+		if len(res.Records) > 0 {
+			fromID = ulidutils.NextID(res.Records[len(res.Records)-1].ID)
+		}
+		if fired {
+			break
+		}
+		if err = runner.Sleep(ctx, pollInterval); err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, err)
+			return
+		}
+	}
+	r.exec.Logger.Infof("%s: %d records of log %s read", r.name, read, log)
+
+	doneCh <- runner.NewStaticScenarioResult(ctx, nil)
+	return
+}
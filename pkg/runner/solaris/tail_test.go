@@ -0,0 +1,64 @@
+package solaris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTailMsgs(t *testing.T) {
+	logger := logging.NewLogger("test")
+	ctx := context.Background()
+
+	res := <-NewConnect(&connectExecutor{name: ConnectName, Logger: logger}, "test").
+		RunScenario(ctx, model.ToScenarioConfig(&ConnectCfg{Address: "inmem://test-tail"}))
+	assert.NoError(t, res.Error())
+	ctx = res.Ctx(ctx)
+	res = <-NewCreateLog(&createLogExecutor{name: CreateLogName, Logger: logger}, "test").
+		RunScenario(ctx, model.ToScenarioConfig(&CreateLogCfg{Tags: map[string]string{"test": "tail"}}))
+	assert.NoError(t, res.Error())
+	ctx = res.Ctx(ctx)
+	res = <-NewAppendMsg(&appendMsgExecutor{name: AppendRunName, Logger: logger}, "test").
+		RunScenario(ctx, model.ToScenarioConfig(&AppendCfg{MessageSize: 16, BatchSize: 10, Number: 3}))
+	assert.NoError(t, res.Error())
+
+	exec := &tailMsgsExecutor{name: TailMsgsRunName, Logger: logger}
+	res = <-NewTailMsgs(exec, "test").RunScenario(ctx, model.ToScenarioConfig(&TailMsgsCfg{
+		Step:          7,
+		Number:        30,
+		FromBeginning: true,
+	}))
+	assert.NoError(t, res.Error())
+
+	// the log deleted while tailed fails the tail after the errors in a row
+	resCh := make(chan error, 1)
+	go func() {
+		resCh <- (<-NewTailMsgs(exec, "test").RunScenario(ctx, model.ToScenarioConfig(&TailMsgsCfg{
+			Step:         10,
+			Number:       100,
+			PollInterval: "10ms",
+			CallCfg: CallCfg{
+				Retry:            &RetryCfg{MaxAttempts: 1, Backoff: "1ms", MaxBackoff: "5ms"},
+				ContinueOn:       []string{"NOT_FOUND"},
+				ContinueMaxInRow: 3,
+			},
+		}))).Error()
+	}()
+	time.Sleep(50 * time.Millisecond)
+	clnt := ctx.Value(solarisClnt).(solaris.ServiceClient)
+	_, err := clnt.DeleteLogs(ctx, &solaris.DeleteLogsRequest{Condition: "tag('test') = 'tail'"})
+	assert.NoError(t, err)
+	select {
+	case err = <-resCh:
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	case <-time.After(5 * time.Second):
+		t.Fatal("tail is not failed after the errors in a row")
+	}
+}
@@ -0,0 +1,159 @@
+package solaris
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	updateLogs struct {
+		exec *updateLogsExecutor
+		name string
+	}
+
+	updateLogsExecutor struct {
+		name     string
+		Registry *runner.Registry `inject:""`
+		Logger   logging.Logger   `inject:""`
+	}
+
+	// UpdateLogsCfg describes the tags mutation of up to Number logs (all
+	// if zero) matching the Condition, the generated tags are merged into
+	// the existing tags of every log. Only the UpdateLog calls are measured.
+	UpdateLogsCfg struct {
+		Condition          string  `yaml:"condition" json:"condition"`
+		Number             int     `yaml:"number,omitempty" json:"number,omitempty"`
+		Step               int64   `yaml:"step,omitempty" json:"step,omitempty"`
+		Tags               TagsCfg `yaml:"tags" json:"tags"`
+		TimeoutMetricName  string  `yaml:"timeoutMetricName,omitempty" json:"timeoutMetricName,omitempty"`
+		LatencyMetricName  string  `yaml:"latencyMetricName,omitempty" json:"latencyMetricName,omitempty"`
+		LogsRateMetricName string  `yaml:"logsRateMetricName,omitempty" json:"logsRateMetricName,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
+	}
+)
+
+const UpdateLogsName = "solaris.updateLogs"
+
+func NewUpdateLogs(exec *updateLogsExecutor, prefix string) runner.ScenarioRunner {
+	return &updateLogs{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), runner.GetRunnerIndex())}
+}
+
+func NewUpdateLogsExecutor() runner.ScenarioExecutor {
+	return &updateLogsExecutor{name: UpdateLogsName}
+}
+
+func (r *updateLogsExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *updateLogsExecutor) Name() string {
+	return r.name
+}
+
+func (r *updateLogsExecutor) New(prefix string) runner.ScenarioRunner {
+	return NewUpdateLogs(r, prefix)
+}
+
+func (r *updateLogs) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan runner.ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *updateLogs) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan runner.ScenarioResult) {
+	doneCh = make(chan runner.ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed))
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[UpdateLogsCfg](config)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse scenario config %w", err))
+		return
+	}
+	if cfg.Step == 0 {
+		cfg.Step = defaultQueryRecordsLimit
+	}
+	tags, err := newTagsGenerator(cfg.Tags)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, err)
+		return
+	}
+
+	clnt, _ := ctx.Value(solarisClnt).(solaris.ServiceClient)
+	if clnt == nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris service not found"))
+		return
+	}
+
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
+	toMetric, _ := runner.GetDurationMetric(ctx, cfg.TimeoutMetricName)
+	latencyMetric, _ := runner.GetHistogramMetric(ctx, cfg.LatencyMetricName)
+	logsInSecMetric, _ := runner.GetRateMetric(ctx, cfg.LogsRateMetricName)
+	updated := 0
+	pageID := ""
+	for cfg.Number == 0 || updated < cfg.Number {
+		res, err := call(ctx, policy, clnt.QueryLogs, &solaris.QueryLogsRequest{
+			Condition: cfg.Condition,
+			PageID:    pageID,
+			Limit:     cfg.Step,
+		})
+		if err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to query logs by condition %q: %w", cfg.Condition, err))
+			return
+		}
+		for _, log := range res.Logs {
+			if cfg.Number > 0 && updated >= cfg.Number {
+				break
+			}
+			if log.Tags == nil {
+				log.Tags = map[string]string{}
+			}
+			for k, v := range tags() {
+				log.Tags[k] = v
+			}
+			_, dur, err := timedCall(ctx, policy, clnt.UpdateLog, log)
+			if err != nil {
+				if policy.tolerate(ctx, err) {
+					continue
+				}
+				doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to update log %s: %w", log.ID, err))
+				return
+			}
+			if toMetric != nil {
+				toMetric.Add(dur.Nanoseconds())
+			}
+			if latencyMetric != nil {
+				latencyMetric.Add(dur.Nanoseconds())
+			}
+			if logsInSecMetric != nil {
+				logsInSecMetric.Add(1, dur)
+			}
+			updated++
+		}
+		pageID = res.NextPageID
+		if pageID == "" {
+			break
+		}
+	}
+	r.exec.Logger.Infof("%s: %d logs updated", r.name, updated)
+
+	doneCh <- runner.NewStaticScenarioResult(ctx, nil)
+	return
+}
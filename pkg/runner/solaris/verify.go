@@ -0,0 +1,145 @@
+package solaris
+
+import (
+	"fmt"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/payload"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+
+	"context"
+)
+
+type (
+	verifyMsgs struct {
+		exec *verifyMsgsExecutor
+		name string
+	}
+
+	verifyMsgsExecutor struct {
+		name     string
+		Registry *runner.Registry `inject:""`
+		Logger   logging.Logger   `inject:""`
+	}
+
+	// VerifyMsgsCfg describes the read of the whole log written by the
+	// solaris.append scenarios with the verify option. The run fails if
+	// any record is missing, duplicated, reordered or corrupted, the
+	// numbers of such records are added to the INT metrics.
+	VerifyMsgsCfg struct {
+		Step                 int64  `yaml:"step" json:"step"`
+		MissingMetricName    string `yaml:"missingMetricName,omitempty" json:"missingMetricName,omitempty"`
+		DuplicatedMetricName string `yaml:"duplicatedMetricName,omitempty" json:"duplicatedMetricName,omitempty"`
+		ReorderedMetricName  string `yaml:"reorderedMetricName,omitempty" json:"reorderedMetricName,omitempty"`
+		CorruptedMetricName  string `yaml:"corruptedMetricName,omitempty" json:"corruptedMetricName,omitempty"`
+		// CallCfg is the deadline and the retries of the Solaris calls
+		CallCfg `yaml:",inline"`
+	}
+)
+
+const VerifyMsgsRunName = "solaris.verify"
+
+func NewVerifyMsgs(exec *verifyMsgsExecutor, prefix string) runner.ScenarioRunner {
+	return &verifyMsgs{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), runner.GetRunnerIndex())}
+}
+
+func NewVerifyMsgsExecutor() runner.ScenarioExecutor {
+	return &verifyMsgsExecutor{name: VerifyMsgsRunName}
+}
+
+func (r *verifyMsgsExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *verifyMsgsExecutor) Name() string {
+	return r.name
+}
+
+func (r *verifyMsgsExecutor) New(prefix string) runner.ScenarioRunner {
+	return NewVerifyMsgs(r, prefix)
+}
+
+func (r *verifyMsgs) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan runner.ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *verifyMsgs) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan runner.ScenarioResult) {
+	doneCh = make(chan runner.ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed))
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[VerifyMsgsCfg](config)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to parse scenario config %w", err))
+		return
+	}
+	if cfg.Step == 0 {
+		cfg.Step = defaultQueryRecordsLimit
+	}
+	clnt, _ := ctx.Value(solarisClnt).(solaris.ServiceClient)
+	if clnt == nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris service not found"))
+		return
+	}
+
+	policy, err := newCallPolicy(ctx, cfg.CallCfg)
+	if err != nil {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("invalid call config: %w", err))
+		return
+	}
+
+	log, _ := ctx.Value(solarisLog).(string)
+	if len(log) == 0 {
+		doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("solaris log not found"))
+		return
+	}
+
+	verifier := payload.NewVerifier()
+	fromID := ""
+	for {
+		req := &solaris.QueryRecordsRequest{
+			LogIDs:        []string{log},
+			Limit:         cfg.Step,
+			StartRecordID: fromID,
+		}
+		res, err := call(ctx, policy, clnt.QueryRecords, req)
+		if err != nil {
+			doneCh <- runner.NewStaticScenarioResult(ctx, fmt.Errorf("failed to query records: %w", err))
+			return
+		}
+		for _, rec := range res.Records {
+			verifier.Add(rec.Payload)
+		}
+		fromID = res.NextPageID
+		if fromID == "" {
+			break
+		}
+	}
+
+	result := verifier.Result()
+	addMetric := func(name string, value int64) {
+		if metric, _ := runner.GetIntMetric(ctx, name); metric != nil {
+			metric.Add(value)
+		}
+	}
+	addMetric(cfg.MissingMetricName, result.Missing)
+	addMetric(cfg.DuplicatedMetricName, result.Duplicated)
+	addMetric(cfg.ReorderedMetricName, result.Reordered)
+	addMetric(cfg.CorruptedMetricName, result.Corrupted)
+	r.exec.Logger.Infof("%s: %d records of log %s verified", r.name, result.Records, log)
+	if err = result.Err(); err != nil {
+		err = fmt.Errorf("log %s integrity is violated: %w", log, err)
+	}
+	doneCh <- runner.NewStaticScenarioResult(ctx, err)
+	return
+}
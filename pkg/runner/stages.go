@@ -0,0 +1,326 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/container"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	stagesRunner struct {
+		exec *stagesExecutor
+		name string
+
+		lock    sync.Mutex
+		wg      sync.WaitGroup
+		errs    map[int]error
+		errsNum int
+		failed  bool
+		index   int
+	}
+
+	stagesExecutor struct {
+		name     string
+		Registry *Registry      `inject:""`
+		Logger   logging.Logger `inject:""`
+	}
+
+	// StagesCfg describes the load profile as the list of stages, the load
+	// changes linearly from the target of the previous stage (zero for the
+	// first one) to the target of the stage during the stage duration.
+	// The stages either control the number of the concurrent virtual users
+	// (VUs) repeating the action, or the arrival rate of the actions.
+	StagesCfg struct {
+		Stages []Stage        `yaml:"stages" json:"stages"`
+		Action model.Scenario `yaml:"action" json:"action"`
+		// MaxInFlight is the max number of the running actions in the rate
+		// mode, the start is dropped if the limit is reached. Zero means no limit.
+		MaxInFlight int  `yaml:"maxInFlight,omitempty" json:"maxInFlight,omitempty"`
+		SkipErrors  bool `yaml:"skipErrors,omitempty" json:"skipErrors,omitempty"`
+		// ActiveMetric is the GAUGE metric of the active VUs (or the running
+		// actions in the rate mode)
+		ActiveMetric string `yaml:"activeMetric,omitempty" json:"activeMetric,omitempty"`
+		// DroppedMetric is the INT metric counting the starts dropped because
+		// of the MaxInFlight limit
+		DroppedMetric string `yaml:"droppedMetric,omitempty" json:"droppedMetric,omitempty"`
+	}
+
+	// Stage is the segment of the load profile, only one of TargetConcurrency
+	// and TargetRate should be used in all the stages
+	Stage struct {
+		Duration          string  `yaml:"duration" json:"duration"`
+		TargetConcurrency int     `yaml:"targetConcurrency,omitempty" json:"targetConcurrency,omitempty"`
+		TargetRate        float64 `yaml:"targetRate,omitempty" json:"targetRate,omitempty"`
+	}
+
+	loadProfile struct {
+		durations []time.Duration
+		targets   []float64
+	}
+
+	// virtualUser repeats the action until it is stopped
+	virtualUser struct {
+		stopCh chan struct{}
+	}
+)
+
+const (
+	StagesRunName = "stages"
+	// stagesTick is how often the number of VUs is adjusted to the profile
+	stagesTick = 100 * time.Millisecond
+	// stagesRateTick is how often the actions are started in the rate mode
+	stagesRateTick = time.Millisecond
+)
+
+func NewStagesRunner(exec *stagesExecutor, prefix string) ScenarioRunner {
+	return &stagesRunner{
+		exec: exec,
+		name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), GetRunnerIndex()),
+		errs: map[int]error{},
+	}
+}
+
+func NewStagesExecutor() ScenarioExecutor {
+	return &stagesExecutor{name: StagesRunName}
+}
+
+func (r *stagesExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *stagesExecutor) Name() string {
+	return r.name
+}
+
+func (r *stagesExecutor) New(prefix string) ScenarioRunner {
+	return NewStagesRunner(r, prefix)
+}
+
+func (r *stagesRunner) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *stagesRunner) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan ScenarioResult) {
+	doneCh = make(chan ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed)}
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[StagesCfg](config)
+	if err != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to parse scenario config %w", err)}
+		return
+	}
+	profile, rateMode, err := newLoadProfile(cfg.Stages)
+	if err != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to parse stages: %w", err)}
+		return
+	}
+	executor, ok := r.exec.Registry.Get(cfg.Action.Name)
+	if !ok {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to get executor %s: %w", cfg.Action.Name, errors.ErrNotExist)}
+		return
+	}
+	activeMetric, _ := GetGaugeMetric(ctx, cfg.ActiveMetric)
+	if activeMetric == nil {
+		activeMetric = metrics.NewGauge()
+	}
+
+	start := time.Now()
+	if rateMode {
+		droppedMetric, _ := GetIntMetric(ctx, cfg.DroppedMetric)
+		r.runRate(ctx, &cfg, profile, executor, activeMetric, droppedMetric)
+	} else {
+		r.runConcurrency(ctx, &cfg, profile, executor, activeMetric)
+	}
+	r.wg.Wait()
+	r.exec.Logger.Infof("%s: %d actions started in %s, max active %d",
+		r.name, r.index, time.Since(start).Round(time.Millisecond), activeMetric.Max())
+
+	r.lock.Lock()
+	errs, errsNum := container.CopyMap(r.errs), r.errsNum
+	r.lock.Unlock()
+	doneCh <- &rateScenarioResult{name: r.name, errs: errs, errsNum: errsNum, skipErrors: cfg.SkipErrors}
+	return
+}
+
+// runConcurrency adjusts the number of VUs to the profile every stagesTick,
+// the stopped VU finishes its current action before exit
+func (r *stagesRunner) runConcurrency(ctx context.Context, cfg *StagesCfg, profile loadProfile, executor ScenarioExecutor, active *metrics.Gauge) {
+	var vus []virtualUser
+	defer func() {
+		for _, vu := range vus {
+			close(vu.stopCh)
+		}
+	}()
+	start := time.Now()
+	ticker := time.NewTicker(stagesTick)
+	defer ticker.Stop()
+	for {
+		target, ok := profile.target(time.Since(start))
+		if !ok || ctx.Err() != nil || r.isFailed() {
+			return
+		}
+		for n := int(math.Round(target)); len(vus) < n; {
+			vu := virtualUser{stopCh: make(chan struct{})}
+			vus = append(vus, vu)
+			r.wg.Add(1)
+			active.Add(1)
+			go func() {
+				defer r.wg.Done()
+				defer active.Add(-1)
+				vuCtx := WithWorker(ctx)
+				for {
+					select {
+					case <-vu.stopCh:
+						return
+					case <-ctx.Done():
+						return
+					default:
+					}
+					if !r.runAction(vuCtx, cfg, executor) {
+						return
+					}
+				}
+			}()
+		}
+		for n := int(math.Round(target)); len(vus) > n; vus = vus[:len(vus)-1] {
+			close(vus[len(vus)-1].stopCh)
+		}
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+}
+
+// runRate starts the actions with the arrival rate of the profile: every
+// stagesRateTick it starts as many actions as the integral of the rate
+// over the elapsed time is
+func (r *stagesRunner) runRate(ctx context.Context, cfg *StagesCfg, profile loadProfile, executor ScenarioExecutor, active *metrics.Gauge, dropped *metrics.Scalar[int64]) {
+	start := time.Now()
+	ticker := time.NewTicker(stagesRateTick)
+	defer ticker.Stop()
+	var scheduled int64
+	for {
+		due, ok := profile.integral(time.Since(start))
+		if !ok || ctx.Err() != nil || r.isFailed() {
+			return
+		}
+		for ; scheduled < int64(due); scheduled++ {
+			if cfg.MaxInFlight > 0 && active.Value() >= int64(cfg.MaxInFlight) {
+				if dropped != nil {
+					dropped.Add(1)
+				}
+				continue
+			}
+			r.wg.Add(1)
+			active.Add(1)
+			go func() {
+				defer r.wg.Done()
+				defer active.Add(-1)
+				r.runAction(WithWorker(ctx), cfg, executor)
+			}()
+		}
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+}
+
+// runAction runs the action once and returns false if the runner should stop
+func (r *stagesRunner) runAction(ctx context.Context, cfg *StagesCfg, executor ScenarioExecutor) bool {
+	r.lock.Lock()
+	index := r.index
+	r.index++
+	r.lock.Unlock()
+	result := <-executor.New(r.name).RunScenario(ctx, ExpandConfig(ctx, cfg.Action.Config))
+	if err := result.Error(); err != nil {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		if len(r.errs) < maxRateErrors {
+			r.errs[index] = err
+		}
+		r.errsNum++
+		r.failed = !cfg.SkipErrors
+		return !r.failed
+	}
+	return true
+}
+
+func (r *stagesRunner) isFailed() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.failed
+}
+
+func newLoadProfile(stages []Stage) (loadProfile, bool, error) {
+	var profile loadProfile
+	if len(stages) == 0 {
+		return profile, false, fmt.Errorf("no stages: %w", errors.ErrInvalid)
+	}
+	var concurrencyMode, rateMode bool
+	for i, stage := range stages {
+		d, err := time.ParseDuration(stage.Duration)
+		if err != nil || d <= 0 {
+			return profile, false, fmt.Errorf("stage#%d has invalid duration %q: %w", i, stage.Duration, errors.ErrInvalid)
+		}
+		if stage.TargetConcurrency < 0 || stage.TargetRate < 0 {
+			return profile, false, fmt.Errorf("stage#%d has negative target: %w", i, errors.ErrInvalid)
+		}
+		concurrencyMode = concurrencyMode || stage.TargetConcurrency > 0
+		rateMode = rateMode || stage.TargetRate > 0
+		profile.durations = append(profile.durations, d)
+		profile.targets = append(profile.targets, float64(stage.TargetConcurrency)+stage.TargetRate)
+	}
+	if concurrencyMode && rateMode {
+		return profile, false, fmt.Errorf("targetConcurrency and targetRate cannot be mixed: %w", errors.ErrInvalid)
+	}
+	return profile, rateMode, nil
+}
+
+// integral returns the integral of the target over the time from the
+// beginning of the profile (the number of the starts in the rate mode),
+// or false if all the stages are passed
+func (p loadProfile) integral(elapsed time.Duration) (float64, bool) {
+	from, sum := 0.0, 0.0
+	for i, d := range p.durations {
+		if elapsed < d {
+			to := from + (p.targets[i]-from)*float64(elapsed)/float64(d)
+			return sum + (from+to)/2*elapsed.Seconds(), true
+		}
+		sum += (from + p.targets[i]) / 2 * d.Seconds()
+		elapsed -= d
+		from = p.targets[i]
+	}
+	return sum, false
+}
+
+// target returns the interpolated target at the moment, or false if
+// all the stages are passed
+func (p loadProfile) target(elapsed time.Duration) (float64, bool) {
+	from := 0.0
+	for i, d := range p.durations {
+		if elapsed < d {
+			return from + (p.targets[i]-from)*float64(elapsed)/float64(d), true
+		}
+		elapsed -= d
+		from = p.targets[i]
+	}
+	return 0, false
+}
@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadProfile(t *testing.T) {
+	profile, rateMode, err := newLoadProfile([]Stage{
+		{Duration: "10s", TargetConcurrency: 10},
+		{Duration: "10s", TargetConcurrency: 10},
+		{Duration: "5s"},
+	})
+	assert.NoError(t, err)
+	assert.False(t, rateMode)
+	for _, tc := range []struct {
+		elapsed time.Duration
+		target  float64
+	}{{0, 0}, {5 * time.Second, 5}, {15 * time.Second, 10}, {22500 * time.Millisecond, 5}} {
+		target, ok := profile.target(tc.elapsed)
+		assert.True(t, ok)
+		assert.InDelta(t, tc.target, target, 0.001)
+	}
+	_, ok := profile.target(25 * time.Second)
+	assert.False(t, ok)
+
+	_, rateMode, err = newLoadProfile([]Stage{{Duration: "1s", TargetRate: 100}})
+	assert.NoError(t, err)
+	assert.True(t, rateMode)
+
+	_, _, err = newLoadProfile([]Stage{{Duration: "1s", TargetRate: 100}, {Duration: "1s", TargetConcurrency: 1}})
+	assert.Error(t, err)
+	_, _, err = newLoadProfile([]Stage{{Duration: "1x", TargetRate: 100}})
+	assert.Error(t, err)
+}
+
+func TestStagesRunner_Concurrency(t *testing.T) {
+	logger := logging.NewLogger("test")
+	registry := newPauseRegistry(t, logger)
+	stagesExec := &stagesExecutor{name: StagesRunName, Registry: registry, Logger: logger}
+
+	active := metrics.NewGauge()
+	ctx := context.WithValue(context.Background(), "active", MetricValue{Value: active, Type: GAUGE})
+	cfg := model.ToScenarioConfig(&StagesCfg{
+		Stages:       []Stage{{Duration: "200ms", TargetConcurrency: 4}, {Duration: "200ms", TargetConcurrency: 4}},
+		ActiveMetric: "active",
+		Action: model.Scenario{
+			Name:   PauseRunName,
+			Config: model.ToScenarioConfig(&PauseCfg{Value: "10ms"}),
+		},
+	})
+	result := <-stagesExec.New("").RunScenario(ctx, cfg)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, int64(4), active.Max())
+	assert.Equal(t, int64(0), active.Value())
+
+	// only the first skipped errors are kept, all of them are counted
+	assert.NoError(t, registry.Register(&errorExecutor{name: ErrorRunName, Logger: logger}))
+	result = <-stagesExec.New("").RunScenario(context.Background(), model.ToScenarioConfig(&StagesCfg{
+		Stages:     []Stage{{Duration: "100ms", TargetConcurrency: 2}, {Duration: "100ms", TargetConcurrency: 2}},
+		Action:     model.Scenario{Name: ErrorRunName, Config: model.ToScenarioConfig(&ErrorCfg{Error: "test"})},
+		SkipErrors: true,
+	}))
+	assert.NoError(t, result.Error())
+	rr := result.(*rateScenarioResult)
+	assert.LessOrEqual(t, len(rr.errs), maxRateErrors)
+	assert.Greater(t, rr.errsNum, maxRateErrors)
+	skippedCtx := result.Ctx(context.WithValue(context.Background(), SkippedErrorsMap, map[string]error{}))
+	assert.Equal(t, int64(rr.errsNum), SkippedErrorsNum(skippedCtx))
+}
+
+func TestLoadProfile_Integral(t *testing.T) {
+	profile, _, err := newLoadProfile([]Stage{
+		{Duration: "10s", TargetRate: 100},
+		{Duration: "10s", TargetRate: 100},
+	})
+	assert.NoError(t, err)
+	starts, ok := profile.integral(10 * time.Second)
+	assert.True(t, ok)
+	assert.InDelta(t, 500, starts, 0.001)
+	starts, ok = profile.integral(15 * time.Second)
+	assert.True(t, ok)
+	assert.InDelta(t, 1000, starts, 0.001)
+	_, ok = profile.integral(20 * time.Second)
+	assert.False(t, ok)
+}
@@ -0,0 +1,101 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+type (
+	// RunSummary is the machine-readable result of all the tests run
+	RunSummary struct {
+		Version    string        `json:"version,omitempty"`
+		StartedAt  time.Time     `json:"startedAt"`
+		FinishedAt time.Time     `json:"finishedAt"`
+		Passed     int           `json:"passed"`
+		Failed     int           `json:"failed"`
+		Tests      []TestSummary `json:"tests"`
+	}
+
+	// TestSummary is the machine-readable result of one test
+	TestSummary struct {
+		Name      string    `json:"name"`
+		Status    string    `json:"status"`
+		StartedAt time.Time `json:"startedAt"`
+		// Duration is the test duration in nanoseconds
+		Duration time.Duration `json:"duration"`
+		// Errors is the error chain of the failed test, from the outermost error to the root cause
+		Errors        []string                 `json:"errors,omitempty"`
+		SkippedErrors map[string]string        `json:"skippedErrors,omitempty"`
+		Metrics       map[string]MetricSummary `json:"metrics,omitempty"`
+	}
+
+	MetricSummary struct {
+		Type   MetricsType `json:"type"`
+		Result any         `json:"result"`
+	}
+)
+
+const (
+	TestPassed = "passed"
+	TestFailed = "failed"
+)
+
+func newTestSummary(name string, start time.Time, resCtx context.Context, err error) TestSummary {
+	ts := TestSummary{
+		Name:      name,
+		Status:    TestPassed,
+		StartedAt: start,
+		Duration:  time.Since(start),
+	}
+	if err != nil {
+		ts.Status = TestFailed
+		ts.Errors = errorChain(err)
+	}
+	if skipped, ok := resCtx.Value(SkippedErrorsMap).(map[string]error); ok && len(skipped) > 0 {
+		ts.SkippedErrors = make(map[string]string, len(skipped))
+		for runner, skippedErr := range skipped {
+			ts.SkippedErrors[runner] = skippedErr.Error()
+		}
+	}
+	if fixed, ok := resCtx.Value(FixedMetricsMap).(map[string]MetricValue); ok && len(fixed) > 0 {
+		ts.Metrics = make(map[string]MetricSummary, len(fixed))
+		for name, mv := range fixed {
+			if res, ok := mv.Result(); ok {
+				ts.Metrics[name] = MetricSummary{Type: mv.Type, Result: res}
+			}
+		}
+	}
+	return ts
+}
+
+// errorChain returns the messages of the wrapped errors, every message
+// is stripped of the message of the error it wraps
+func errorChain(err error) []string {
+	var chain []string
+	for err != nil {
+		msg := err.Error()
+		next := errors.Unwrap(err)
+		if next != nil {
+			msg = strings.TrimSpace(strings.TrimSuffix(msg, next.Error()))
+			msg = strings.TrimSpace(strings.TrimSuffix(msg, ":"))
+		}
+		if len(msg) > 0 {
+			chain = append(chain, msg)
+		}
+		err = next
+	}
+	return chain
+}
+
+// FailedTests returns the names of the failed tests
+func (s *RunSummary) FailedTests() []string {
+	var names []string
+	for _, ts := range s.Tests {
+		if ts.Status != TestPassed {
+			names = append(names, ts.Name)
+		}
+	}
+	return names
+}
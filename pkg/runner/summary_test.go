@@ -0,0 +1,30 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTestSummary(t *testing.T) {
+	counter := metrics.NewScalar[int64]()
+	counter.Add(3)
+	ctx := context.WithValue(context.Background(), SkippedErrorsMap, map[string]error{"/append-1": fmt.Errorf("timeout")})
+	ctx = context.WithValue(ctx, FixedMetricsMap, map[string]MetricValue{"Appends": {Value: counter, Type: INT}})
+
+	err := fmt.Errorf("test failed: %w", fmt.Errorf("step failed: %w", fmt.Errorf("timeout")))
+	ts := newTestSummary("test", time.Now(), ctx, err)
+	assert.Equal(t, TestFailed, ts.Status)
+	assert.Equal(t, []string{"test failed", "step failed", "timeout"}, ts.Errors)
+	assert.Equal(t, map[string]string{"/append-1": "timeout"}, ts.SkippedErrors)
+	assert.Equal(t, INT, ts.Metrics["Appends"].Type)
+	assert.Equal(t, int64(1), ts.Metrics["Appends"].Result.(metrics.IntMetricResult).Total)
+
+	ts = newTestSummary("test", time.Now(), context.Background(), nil)
+	assert.Equal(t, TestPassed, ts.Status)
+	assert.Empty(t, ts.Errors)
+}
@@ -0,0 +1,202 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+)
+
+type (
+	// Trigger is the context.Context which is done when the trigger is
+	// fired, so it may be awaited by the await scenario
+	Trigger struct {
+		context.Context
+		cancel context.CancelFunc
+		count  atomic.Int64
+	}
+
+	triggerCreateRunner struct {
+		exec *triggerCreateExecutor
+		name string
+	}
+	triggerCreateExecutor struct {
+		name     string
+		Registry *Registry      `inject:""`
+		Logger   logging.Logger `inject:""`
+	}
+
+	// TriggerCreateCfg describes the trigger added to the context under
+	// the Name, the steps run after the trigger.create (e.g. the steps of
+	// the following parallel scenario) share the trigger
+	TriggerCreateCfg struct {
+		Name string `yaml:"name" json:"name"`
+		// Count is the number of the trigger.fire runs the trigger is fired
+		// after, 1 by default. E.g. the trigger with the count of the
+		// parallel steps is fired when all the steps reached the trigger.fire.
+		Count int `yaml:"count,omitempty" json:"count,omitempty"`
+	}
+
+	triggerFireRunner struct {
+		exec *triggerFireExecutor
+		name string
+	}
+	triggerFireExecutor struct {
+		name     string
+		Registry *Registry      `inject:""`
+		Logger   logging.Logger `inject:""`
+	}
+
+	// TriggerFireCfg describes the firing of the trigger
+	TriggerFireCfg struct {
+		Name string `yaml:"name" json:"name"`
+		// Await enables awaiting the trigger after the firing, so the
+		// trigger with the count of the parallel steps is the barrier
+		// all the steps pass at the same instant
+		Await bool `yaml:"await,omitempty" json:"await,omitempty"`
+		// Timeout limits the awaiting, no limit if empty
+		Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	}
+
+	triggerScenarioResult struct {
+		name    string
+		trigger *Trigger
+	}
+)
+
+const (
+	TriggerCreateRunName = "trigger.create"
+	TriggerFireRunName   = "trigger.fire"
+)
+
+// NewTrigger returns the trigger fired after count Fire calls
+func NewTrigger(count int) *Trigger {
+	t := &Trigger{}
+	t.Context, t.cancel = context.WithCancel(context.Background())
+	t.count.Store(int64(max(count, 1)))
+	return t
+}
+
+// Fire counts the firing, the trigger is done when it is fired the count times
+func (t *Trigger) Fire() {
+	if t.count.Add(-1) <= 0 {
+		t.cancel()
+	}
+}
+
+func NewTriggerCreateRunner(exec *triggerCreateExecutor, prefix string) ScenarioRunner {
+	return &triggerCreateRunner{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), GetRunnerIndex())}
+}
+
+func NewTriggerCreateExecutor() ScenarioExecutor {
+	return &triggerCreateExecutor{name: TriggerCreateRunName}
+}
+
+func (r *triggerCreateExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *triggerCreateExecutor) Name() string {
+	return r.name
+}
+
+func (r *triggerCreateExecutor) New(prefix string) ScenarioRunner {
+	return NewTriggerCreateRunner(r, prefix)
+}
+
+func (r *triggerCreateRunner) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *triggerCreateRunner) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan ScenarioResult) {
+	doneCh = make(chan ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed)}
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[TriggerCreateCfg](config)
+	if err != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to parse scenario config %w", err)}
+		return
+	}
+	if len(cfg.Name) == 0 {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("trigger name is empty: %w", errors.ErrInvalid)}
+		return
+	}
+	doneCh <- &triggerScenarioResult{name: cfg.Name, trigger: NewTrigger(cfg.Count)}
+	return
+}
+
+func (r *triggerScenarioResult) Ctx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, r.name, r.trigger)
+}
+
+func (r *triggerScenarioResult) Error() error {
+	return nil
+}
+
+func NewTriggerFireRunner(exec *triggerFireExecutor, prefix string) ScenarioRunner {
+	return &triggerFireRunner{exec: exec, name: fmt.Sprintf("%s/%s-%d", prefix, exec.Name(), GetRunnerIndex())}
+}
+
+func NewTriggerFireExecutor() ScenarioExecutor {
+	return &triggerFireExecutor{name: TriggerFireRunName}
+}
+
+func (r *triggerFireExecutor) Init(ctx context.Context) error {
+	return r.Registry.Register(r)
+}
+
+func (r *triggerFireExecutor) Name() string {
+	return r.name
+}
+
+func (r *triggerFireExecutor) New(prefix string) ScenarioRunner {
+	return NewTriggerFireRunner(r, prefix)
+}
+
+func (r *triggerFireRunner) RunScenario(ctx context.Context, config *model.ScenarioConfig) <-chan ScenarioResult {
+	r.exec.Logger.Debugf("Running scenario %s", r.name)
+	defer r.exec.Logger.Debugf("Scenario finished %s", r.name)
+
+	return r.run(ctx, config)
+}
+
+func (r *triggerFireRunner) run(ctx context.Context, config *model.ScenarioConfig) (doneCh chan ScenarioResult) {
+	doneCh = make(chan ScenarioResult, 1)
+	defer close(doneCh)
+
+	if ctx.Err() != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("run context is closed %w", errors.ErrClosed)}
+		return
+	}
+
+	cfg, err := model.FromScenarioConfig[TriggerFireCfg](config)
+	if err != nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("failed to parse scenario config %w", err)}
+		return
+	}
+	trigger, _ := ctx.Value(cfg.Name).(*Trigger)
+	if trigger == nil {
+		doneCh <- &staticScenarioResult{ctx, fmt.Errorf("trigger %s not found: %w", cfg.Name, errors.ErrNotExist)}
+		return
+	}
+	trigger.Fire()
+	if cfg.Await {
+		if err = awaitTrigger(ctx, cfg.Name, trigger, cfg.Timeout); err != nil {
+			doneCh <- &staticScenarioResult{ctx, err}
+			return
+		}
+	}
+	doneCh <- &staticScenarioResult{ctx: ctx, error: nil}
+	return
+}
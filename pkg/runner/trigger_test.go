@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrigger(t *testing.T) {
+	registry := NewRegistry()
+	logger := logging.NewLogger("test")
+	assert.NoError(t, registry.Register(&sequenceExecutor{Registry: registry, Logger: logger}))
+	assert.NoError(t, registry.Register(&parallelExecutor{name: ParallelRunName, Registry: registry, Logger: logger}))
+	assert.NoError(t, registry.Register(&awaitExecutor{name: AwaitRunName, Registry: registry, Logger: logger}))
+	assert.NoError(t, registry.Register(&triggerCreateExecutor{name: TriggerCreateRunName, Registry: registry, Logger: logger}))
+	assert.NoError(t, registry.Register(&triggerFireExecutor{name: TriggerFireRunName, Registry: registry, Logger: logger}))
+
+	barrier := func(steps, count int) *model.ScenarioConfig {
+		return model.ToScenarioConfig(&SequenceCfg{
+			Steps: []model.Scenario{
+				{Name: TriggerCreateRunName, Config: model.ToScenarioConfig(&TriggerCreateCfg{Name: "start", Count: count})},
+				{Name: ParallelRunName, Config: model.ToScenarioConfig(&ParallelCfg{
+					Action: &model.Scenario{Name: TriggerFireRunName, Config: model.ToScenarioConfig(&TriggerFireCfg{Name: "start", Await: true, Timeout: "50ms"})},
+					Count:  steps,
+				})},
+			},
+		})
+	}
+	executor, _ := registry.Get(SequenceRunName)
+	ctx := context.Background()
+	res := <-executor.New("test").RunScenario(ctx, barrier(3, 3))
+	assert.NoError(t, res.Error())
+	res = <-executor.New("test").RunScenario(ctx, barrier(2, 3))
+	assert.ErrorContains(t, res.Error(), "is not fired")
+
+	executor, _ = registry.Get(AwaitRunName)
+	res = <-executor.New("test").RunScenario(ctx, model.ToScenarioConfig(&AwaitCfg{TriggerName: "unknown"}))
+	assert.ErrorIs(t, res.Error(), errors.ErrNotExist)
+
+	trigger := NewTrigger(2)
+	trigger.Fire()
+	assert.NoError(t, trigger.Err())
+	trigger.Fire()
+	assert.Error(t, trigger.Err())
+}
@@ -18,6 +18,24 @@ func GetRunnerIndex() int {
 	return int(atomic.AddInt32(&runnerCouner, 1))
 }
 
+// WorkerKey is the context key of the worker index, the concurrent runners
+// (parallel, loop, stages and rate) run every worker with its own index
+const WorkerKey = "worker"
+
+var workerCounter atomic.Int64
+
+// WithWorker returns the context of the new worker
+func WithWorker(ctx context.Context) context.Context {
+	return context.WithValue(ctx, WorkerKey, workerCounter.Add(1))
+}
+
+// GetWorker returns the index of the worker the context belongs to, 0 if
+// the context does not belong to any
+func GetWorker(ctx context.Context) int64 {
+	worker, _ := ctx.Value(WorkerKey).(int64)
+	return worker
+}
+
 type (
 	staticScenarioResult struct {
 		ctx   context.Context
@@ -150,6 +168,22 @@ func (sw *StopWaiter) AwaitStop() {
 	sw.wg.Wait()
 }
 
+// Sleep pauses the current goroutine for the duration, it returns an error
+// if the context is closed before the duration is elapsed
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("run context is closed %w", errors2.ErrClosed)
+	case <-timer.C:
+		return nil
+	}
+}
+
 func DelayedAction(delay time.Duration, action *model.Scenario) (delayedAction *model.Scenario) {
 	if delay > 0 {
 		delayedAction = &model.Scenario{
@@ -83,7 +83,7 @@ func (r *weightedRunner) run(ctx context.Context, config *model.ScenarioConfig)
 	}
 
 	// Pass scenario result to parent
-	doneCh <- <-stepRunner.New(r.name).RunScenario(ctx, pStep.Config)
+	doneCh <- <-stepRunner.New(r.name).RunScenario(ctx, ExpandConfig(ctx, pStep.Config))
 	return doneCh
 }
 
@@ -14,21 +14,69 @@ import (
 var defaultAddress = "localhost:50051"
 var defaultEnvVarAddress = "PERFTESTS_SOLARIS_ADDRESS"
 var defaultEnvRunID = "PERFTESTS_RUN_ID"
+var defaultEnvNodes = "PERFTESTS_NODES"
 var defLogLevel = "info"
+var defaultQueryLogsStep int64 = 1000
 
 var oneKb = int(math.Pow(float64(2), float64(10)))
 var oneMB = oneKb * oneKb
 var oneGB = oneKb * oneMB
 
 const appendToMetricName = "AppendTimeout"
+const appendLatencyMetricName = "AppendLatency"
 const appendMsgsPerSecMetricName = "AppendMsgsInSec"
 const appendBytesPerSecMetricName = "AppendBytesInSec"
 const queryToMetricName = "QueryTimeout"
+const queryLatencyMetricName = "QueryLatency"
 const queryMsgsPerSecMetricName = "QueryMsgsInSec"
 const queryBytesPerSecMetricName = "QueryBytesInSec"
+const createLogsToMetricName = "CreateLogsTimeout"
+const createLogsLatencyMetricName = "CreateLogsLatency"
+const createLogsPerSecMetricName = "CreateLogsInSec"
+const queryLogsToMetricName = "QueryLogsTimeout"
+const queryLogsLatencyMetricName = "QueryLogsLatency"
+const queryLogsPerSecMetricName = "QueryLogsInSec"
+const updateLogsToMetricName = "UpdateLogsTimeout"
+const updateLogsLatencyMetricName = "UpdateLogsLatency"
+const updateLogsPerSecMetricName = "UpdateLogsInSec"
+const appendErrorsMetricName = "AppendErrors"
+const queryErrorsMetricName = "QueryErrors"
+const createLogsErrorsMetricName = "CreateLogsErrors"
+const queryLogsErrorsMetricName = "QueryLogsErrors"
+const updateLogsErrorsMetricName = "UpdateLogsErrors"
+
+// datasetTag is the tag of the logs kept in the dataset
+const datasetTag = "dataset"
+
+// datasetLogSet is the log set of the reused dataset logs
+const datasetLogSet = "datasetLogs"
+
+const (
+	// metaLogName is the logName tag of the logs created by the metadata tests
+	metaLogName = "meta"
+	// metaNodeTag is the tag of the cluster node ID the metadata test logs are created by
+	metaNodeTag = "node"
+)
 
 type (
-	OpType    string
+	OpType string
+
+	// Dataset describes the tagged logs kept between the runs, so the read
+	// tests can be repeated without filling the logs every time
+	Dataset struct {
+		// Name - the value of the dataset tag of the logs
+		Name string
+		// Keep - if true, the filled logs are tagged and not deleted
+		Keep bool
+		// Reuse - if true, the logs of the dataset are read instead of filling new ones
+		Reuse bool
+	}
+
+	CleanupCfg struct {
+		// Dataset - if not empty, the logs of the dataset are deleted as well
+		Dataset string
+	}
+
 	AppendCfg struct {
 		// ConcurrentLogs - how many logs are written concurrently
 		ConcurrentLogs int
@@ -40,6 +88,8 @@ type (
 		BatchSize int
 		// MsgSize - message size in bytes
 		MsgSize int
+		// Dataset - the dataset the logs are kept in
+		Dataset Dataset
 	}
 
 	QueryCfg struct {
@@ -53,6 +103,23 @@ type (
 		QueryStep int
 		// MsgSize - message size in bytes
 		MsgSize int
+		// Dataset - the dataset the logs are kept in or read from
+		Dataset Dataset
+	}
+
+	LogsMetaCfg struct {
+		// Logs - how many logs are created
+		Logs int
+		// Creators - how many creators create the logs concurrently
+		Creators int
+		// TagsPerLog - how many generated tags every log has
+		TagsPerLog int
+		// Cardinality - how many distinct values every tag has
+		Cardinality int
+		// Readers - how many readers query the logs concurrently
+		Readers int
+		// Queries - how many queries are run by every reader
+		Queries int
 	}
 )
 
@@ -62,6 +129,7 @@ const (
 	Sleep     OpType = "sleep"
 	SeqQuery  OpType = "seq_query"
 	RandQuery OpType = "rand_query"
+	LogsMeta  OpType = "logs_meta"
 )
 
 func BuildConfig(opType OpType, params any) *model.Config {
@@ -70,8 +138,12 @@ func BuildConfig(opType OpType, params any) *model.Config {
 		cfg, _ := params.(*AppendCfg)
 		return buildAppendToManyLogsTests(cfg)
 	case Cleanup:
+		cfg, _ := params.(*CleanupCfg)
+		if cfg == nil {
+			cfg = &CleanupCfg{}
+		}
 		return &model.Config{
-			Tests: []model.Test{*cleanupCluster()},
+			Tests: []model.Test{*cleanupCluster(cfg.Dataset)},
 		}
 	case Sleep:
 		return &model.Config{
@@ -83,18 +155,76 @@ func BuildConfig(opType OpType, params any) *model.Config {
 	case RandQuery:
 		cfg, _ := params.(*QueryCfg)
 		return buildRandQueryLogsTests(cfg)
+	case LogsMeta:
+		cfg, _ := params.(*LogsMetaCfg)
+		return buildLogsMetaTests(cfg)
 	}
 	return &model.Config{}
 }
 
-func cleanupCluster() *model.Test {
+func cleanupCluster(dataset string) *model.Test {
 	scenario := clusterCleanup(defaultEnvRunID, defaultAddress, defaultEnvVarAddress)
+	if len(dataset) == 0 {
+		return &model.Test{
+			Name:     fmt.Sprintf("Cleanup cluster"),
+			Scenario: *scenario,
+		}
+	}
 	return &model.Test{
-		Name:     fmt.Sprintf("Cleanup cluster"),
-		Scenario: *scenario,
+		Name: fmt.Sprintf("Cleanup cluster and dataset %s", dataset),
+		Scenario: model.Scenario{
+			Name: runner.SequenceRunName,
+			Config: model.ToScenarioConfig(&runner.SequenceCfg{
+				Steps: []model.Scenario{
+					*scenario,
+					*datasetCleanup(defaultAddress, defaultEnvVarAddress, dataset),
+				},
+			}),
+		},
+	}
+}
+
+// datasetCleanup deletes all the logs of the dataset
+func datasetCleanup(svcAddress, envVarAddress, dataset string) *model.Scenario {
+	return &model.Scenario{
+		Name: runner.SequenceRunName,
+		Config: model.ToScenarioConfig(&runner.SequenceCfg{
+			Steps: []model.Scenario{
+				// connect to solaris
+				{
+					Name: solaris.ConnectName,
+					Config: model.ToScenarioConfig(&solaris.ConnectCfg{
+						Address:       svcAddress,
+						EnvVarAddress: envVarAddress,
+					}),
+				},
+				// delete the dataset logs
+				{
+					Name: solaris.DeleteLogName,
+					Config: model.ToScenarioConfig(&solaris.DeleteLogCfg{
+						Condition: datasetCondition(dataset),
+					}),
+				},
+			},
+		}),
 	}
 }
 
+func datasetCondition(dataset string) string {
+	return fmt.Sprintf("tag('%s') = '%s'", datasetTag, dataset)
+}
+
+// datasetNote returns the test name suffix describing the dataset
+func datasetNote(ds Dataset) string {
+	switch {
+	case ds.Reuse:
+		return fmt.Sprintf(", the logs of dataset %s are read", ds.Name)
+	case ds.Keep:
+		return fmt.Sprintf(", the logs are kept in dataset %s", ds.Name)
+	}
+	return ""
+}
+
 func pause() *model.Test {
 	scenario := &model.Scenario{
 		Name: runner.PauseRunName,
@@ -111,7 +241,7 @@ func pause() *model.Test {
 func buildSeqQueryLogsTests(cfg *QueryCfg) *model.Config {
 	return &model.Config{
 		Tests: []model.Test{
-			*fillAndSeqReadManyLogs(cfg.ConcurrentLogs, cfg.ReadersFromOneLog, cfg.LogSize, cfg.QueryStep, cfg.MsgSize),
+			*fillAndSeqReadManyLogs(cfg.ConcurrentLogs, cfg.ReadersFromOneLog, cfg.LogSize, cfg.QueryStep, cfg.MsgSize, cfg.Dataset),
 		},
 	}
 }
@@ -119,7 +249,7 @@ func buildSeqQueryLogsTests(cfg *QueryCfg) *model.Config {
 func buildRandQueryLogsTests(cfg *QueryCfg) *model.Config {
 	return &model.Config{
 		Tests: []model.Test{
-			*fillAndRandReadManyLogs(cfg.ConcurrentLogs, cfg.ReadersFromOneLog, cfg.LogSize, cfg.QueryStep, cfg.MsgSize),
+			*fillAndRandReadManyLogs(cfg.ConcurrentLogs, cfg.ReadersFromOneLog, cfg.LogSize, cfg.QueryStep, cfg.MsgSize, cfg.Dataset),
 		},
 	}
 }
@@ -128,13 +258,13 @@ func buildAppendToManyLogsTests(cfg *AppendCfg) *model.Config {
 	return &model.Config{
 		Log: model.LoggingConfig{Level: defLogLevel},
 		Tests: []model.Test{
-			*appendToManyLogs(cfg.ConcurrentLogs, cfg.WritersForOneLog, cfg.LogSize, cfg.BatchSize, cfg.MsgSize),
+			*appendToManyLogs(cfg.ConcurrentLogs, cfg.WritersForOneLog, cfg.LogSize, cfg.BatchSize, cfg.MsgSize, cfg.Dataset),
 		},
 	}
 }
 
 // fillAndRandReadManyLogs fills then reads logs
-func fillAndRandReadManyLogs(concurrentLogs, readers int, logSize, queryStep, msgSize int) *model.Test {
+func fillAndRandReadManyLogs(concurrentLogs, readers int, logSize, queryStep, msgSize int, ds Dataset) *model.Test {
 	appendBatchSize := 50 * oneMB
 	batchSize := appendBatchSize / msgSize
 	appendsToLog := logSize / msgSize / batchSize
@@ -142,18 +272,19 @@ func fillAndRandReadManyLogs(concurrentLogs, readers int, logSize, queryStep, ms
 	test := appendToLogsThenQueryTest(defaultEnvRunID, defaultAddress, defaultEnvVarAddress,
 		concurrentLogs,
 		1, appendsToLog, batchSize, msgSize,
-		false, readers, queryStep, readCount)
-	test.Name = fmt.Sprintf("Rand read %d logs (by %d readers from each one), read %s by each reader sequentially from earliest to lates, one query: %d messages by %s",
+		false, readers, queryStep, readCount, ds)
+	test.Name = fmt.Sprintf("Rand read %d logs (by %d readers from each one), read %s by each reader sequentially from earliest to lates, one query: %d messages by %s%s",
 		concurrentLogs,
 		readers,
 		utils.HumanReadableBytes(float64(logSize)),
 		queryStep,
-		utils.HumanReadableBytes(float64(msgSize)))
+		utils.HumanReadableBytes(float64(msgSize)),
+		datasetNote(ds))
 	return test
 }
 
 // fillAndSeqReadManyLogs fills then reads logs
-func fillAndSeqReadManyLogs(concurrentLogs, readers int, logSize, queryStep, msgSize int) *model.Test {
+func fillAndSeqReadManyLogs(concurrentLogs, readers int, logSize, queryStep, msgSize int, ds Dataset) *model.Test {
 	appendBatchSize := 50 * oneMB
 	batchSize := appendBatchSize / msgSize
 	appendsToLog := logSize / msgSize / batchSize
@@ -161,26 +292,28 @@ func fillAndSeqReadManyLogs(concurrentLogs, readers int, logSize, queryStep, msg
 	test := appendToLogsThenQueryTest(defaultEnvRunID, defaultAddress, defaultEnvVarAddress,
 		concurrentLogs,
 		1, appendsToLog, batchSize, msgSize,
-		true, readers, queryStep, readCount)
-	test.Name = fmt.Sprintf("Seq read %d logs (by %d readers from each one), read %s by each reader sequentially from earliest to lates, one query: %d messages by %s",
+		true, readers, queryStep, readCount, ds)
+	test.Name = fmt.Sprintf("Seq read %d logs (by %d readers from each one), read %s by each reader sequentially from earliest to lates, one query: %d messages by %s%s",
 		concurrentLogs,
 		readers,
 		utils.HumanReadableBytes(float64(logSize)),
 		queryStep,
-		utils.HumanReadableBytes(float64(msgSize)))
+		utils.HumanReadableBytes(float64(msgSize)),
+		datasetNote(ds))
 	return test
 }
 
 // appendToManyLogs appends to concurrentLogs logs
-func appendToManyLogs(concurrentLogs, writers int, logSize, batchSize, oneStep int) *model.Test {
+func appendToManyLogs(concurrentLogs, writers int, logSize, batchSize, oneStep int, ds Dataset) *model.Test {
 	appendsToLog := logSize / writers / oneStep / batchSize
-	test := appendToLogsThenQueryTest(defaultEnvRunID, defaultAddress, defaultEnvVarAddress, concurrentLogs, writers, appendsToLog, batchSize, oneStep, true, 0, 100, -1)
-	test.Name = fmt.Sprintf("Append to %d logs (by %d writers to each one), write %s to each log, one append: %d messages by %s",
+	test := appendToLogsThenQueryTest(defaultEnvRunID, defaultAddress, defaultEnvVarAddress, concurrentLogs, writers, appendsToLog, batchSize, oneStep, true, 0, 100, -1, ds)
+	test.Name = fmt.Sprintf("Append to %d logs (by %d writers to each one), write %s to each log, one append: %d messages by %s%s",
 		concurrentLogs,
 		writers,
 		utils.HumanReadableBytes(float64(logSize)),
 		batchSize,
-		utils.HumanReadableBytes(float64(oneStep)))
+		utils.HumanReadableBytes(float64(oneStep)),
+		datasetNote(ds))
 	return test
 }
 
@@ -193,18 +326,24 @@ func appendToManyLogs(concurrentLogs, writers int, logSize, batchSize, oneStep i
 // logReaders - how many readers to one log work concurrently
 // queryStep -  how many records are read on one Query call
 // queriesNumber - how many Query() will be called for one log
+// ds - the dataset the logs are kept in or read from
 func appendToLogsThenQueryTest(runID, svcAddress, envVarAddress string, concurrentLogs, writersToLog, appendsToLog, batchSize, msgSize int,
-	seqReadMode bool, logReaders, queryStep, queriesNumber int) *model.Test {
+	seqReadMode bool, logReaders, queryStep, queriesNumber int, ds Dataset) *model.Test {
 	scenario := appendToLogsThenQueryScenario(svcAddress, envVarAddress, concurrentLogs, writersToLog, appendsToLog, batchSize, msgSize,
-		seqReadMode, logReaders, queryStep, queriesNumber)
-	scenario = clusterRun(runID, svcAddress, envVarAddress, scenario)
+		seqReadMode, logReaders, queryStep, queriesNumber, ds)
+	scenario = clusterRun(runID, svcAddress, envVarAddress, scenario, map[runner.MetricsType][]string{
+		runner.DURATION:  {appendToMetricName, queryToMetricName},
+		runner.HISTOGRAM: {appendLatencyMetricName, queryLatencyMetricName},
+		runner.RPS:       {appendMsgsPerSecMetricName, appendBytesPerSecMetricName, queryMsgsPerSecMetricName, queryBytesPerSecMetricName},
+		runner.COUNTERS:  {appendErrorsMetricName, queryErrorsMetricName},
+	})
 	return &model.Test{
 		Name:     fmt.Sprintf("Append to %d logs then read it", concurrentLogs),
 		Scenario: *scenario,
 	}
 }
 
-func clusterRun(runID, svcAddress, envVarAddress string, wrappedScenario *model.Scenario) *model.Scenario {
+func clusterRun(runID, svcAddress, envVarAddress string, wrappedScenario *model.Scenario, metrics map[runner.MetricsType][]string) *model.Scenario {
 	return &model.Scenario{
 		Name: runner.SequenceRunName,
 		Config: model.ToScenarioConfig(&runner.SequenceCfg{
@@ -216,6 +355,8 @@ func clusterRun(runID, svcAddress, envVarAddress string, wrappedScenario *model.
 						Address:       svcAddress,
 						EnvVarAddress: envVarAddress,
 						EnvRunID:      runID,
+						Nodes:         1,
+						EnvNodes:      defaultEnvNodes,
 					}),
 				},
 				*wrappedScenario,
@@ -223,11 +364,8 @@ func clusterRun(runID, svcAddress, envVarAddress string, wrappedScenario *model.
 				{
 					Name: cluster.FinishName,
 					Config: model.ToScenarioConfig(&cluster.FinishCfg{
-						Await: true,
-						Metrics: map[runner.MetricsType][]string{
-							runner.DURATION: {appendToMetricName, queryToMetricName},
-							runner.RPS:      {appendMsgsPerSecMetricName, appendBytesPerSecMetricName, queryMsgsPerSecMetricName, queryBytesPerSecMetricName},
-						},
+						Await:   true,
+						Metrics: metrics,
 					}),
 				},
 				// delete cluster
@@ -251,6 +389,7 @@ func clusterCleanup(runID, svcAddress, envVarAddress string) *model.Scenario {
 						Address:       svcAddress,
 						EnvVarAddress: envVarAddress,
 						EnvRunID:      runID,
+						NoNodeInfo:    true,
 					}),
 				},
 				// delete cluster
@@ -263,78 +402,116 @@ func clusterCleanup(runID, svcAddress, envVarAddress string) *model.Scenario {
 }
 
 func appendToLogsThenQueryScenario(svcAddress, envVarAddress string, concurrentLogs, writersToLog, appendsToLog, batchSize, msgSize int,
-	seqReadMode bool, logReaders, queryStep, queriesNumber int) *model.Scenario {
+	seqReadMode bool, logReaders, queryStep, queriesNumber int, ds Dataset) *model.Scenario {
 	appendMetricName := appendToMetricName
 	queryMetricName := queryToMetricName
+	appendLatencyMName := appendLatencyMetricName
+	queryLatencyMName := queryLatencyMetricName
 	appendMsgsMName := appendMsgsPerSecMetricName
 	appendBytesMName := appendBytesPerSecMetricName
 	queryMsgsMName := queryMsgsPerSecMetricName
 	queryBytesMName := queryBytesPerSecMetricName
-	return &model.Scenario{
-		Name: runner.SequenceRunName,
-		Config: model.ToScenarioConfig(&runner.SequenceCfg{
-			Steps: []model.Scenario{
-				// connect to solaris
-				{
-					Name: solaris.ConnectName,
-					Config: model.ToScenarioConfig(&solaris.ConnectCfg{
-						Address:       svcAddress,
-						EnvVarAddress: envVarAddress,
-					}),
-				},
-				// init metrics
-				{
-					Name: runner.MetricsCreateRunName,
-					Config: model.ToScenarioConfig(&runner.MetricsCreateCfg{
-						Metrics: map[runner.MetricsType][]string{
-							runner.DURATION: {appendMetricName, queryMetricName},
-							runner.RPS:      {appendMsgsMName, appendBytesMName, queryMsgsMName, queryBytesMName},
-						},
-					}),
-				},
-				// append to 'concurrentLogs' number of logs in parallel, each appender adds 100 messages in parallel
-				{
-					Name: runner.RepeatRunName,
-					Config: model.ToScenarioConfig(&runner.RepeatCfg{
-						Count:    concurrentLogs,
-						Executor: runner.ParallelRunName,
-						Action: model.Scenario{
-							Name: runner.SequenceRunName,
-							Config: model.ToScenarioConfig(&runner.SequenceCfg{
-								Steps: []model.Scenario{
-									// create log
-									{
-										Name: solaris.CreateLogName,
-										Config: model.ToScenarioConfig(&solaris.CreateLogCfg{
-											Tags: map[string]string{"logName": "foo"},
-										}),
-									},
-									// start 'writersToLog' concurrent writers
-									writeConcurrently(writersToLog, appendsToLog, batchSize, msgSize, appendMetricName, appendMsgsMName, appendBytesMName),
-									// start 'readers' concurrent readers
-									readConcurrently(seqReadMode, logReaders, queryStep, queriesNumber, queryMetricName, queryMsgsMName, queryBytesMName),
-									// delete log
-									{
-										Name: solaris.DeleteLogName,
-									},
-								},
-							}),
-						},
-					}),
+	appendErrorsMName := appendErrorsMetricName
+	queryErrorsMName := queryErrorsMetricName
+
+	var logSteps []model.Scenario
+	if ds.Reuse {
+		// take the next log of the dataset
+		logSteps = append(logSteps, model.Scenario{
+			Name: solaris.SelectLogsName,
+			Config: model.ToScenarioConfig(&solaris.SelectLogsCfg{
+				FromLogSet: datasetLogSet,
+			}),
+		})
+	} else {
+		tags := map[string]string{"logName": "foo"}
+		if ds.Keep {
+			tags[datasetTag] = ds.Name
+		}
+		logSteps = append(logSteps,
+			// create log
+			model.Scenario{
+				Name: solaris.CreateLogName,
+				Config: model.ToScenarioConfig(&solaris.CreateLogCfg{
+					Tags: tags,
+				}),
+			},
+			// start 'writersToLog' concurrent writers
+			writeConcurrently(writersToLog, appendsToLog, batchSize, msgSize, appendMetricName, appendLatencyMName, appendMsgsMName, appendBytesMName, appendErrorsMName))
+	}
+	// start 'readers' concurrent readers
+	logSteps = append(logSteps, readConcurrently(seqReadMode, logReaders, queryStep, queriesNumber, queryMetricName, queryLatencyMName, queryMsgsMName, queryBytesMName, queryErrorsMName))
+	if !ds.Keep && !ds.Reuse {
+		// delete log
+		logSteps = append(logSteps, model.Scenario{
+			Name: solaris.DeleteLogName,
+		})
+	}
+
+	steps := []model.Scenario{
+		// connect to solaris
+		{
+			Name: solaris.ConnectName,
+			Config: model.ToScenarioConfig(&solaris.ConnectCfg{
+				Address:       svcAddress,
+				EnvVarAddress: envVarAddress,
+			}),
+		},
+		// init metrics
+		{
+			Name: runner.MetricsCreateRunName,
+			Config: model.ToScenarioConfig(&runner.MetricsCreateCfg{
+				Metrics: map[runner.MetricsType][]string{
+					runner.DURATION:  {appendMetricName, queryMetricName},
+					runner.HISTOGRAM: {appendLatencyMName, queryLatencyMName},
+					runner.RPS:       {appendMsgsMName, appendBytesMName, queryMsgsMName, queryBytesMName},
+					runner.COUNTERS:  {appendErrorsMName, queryErrorsMName},
 				},
-				// trace metrics
-				{
-					Name: runner.MetricsFixRunName,
-					Config: model.ToScenarioConfig(&runner.MetricsFixCfg{
-						Metrics: []string{appendMetricName, queryMetricName, appendMsgsMName, appendBytesMName, queryMsgsMName, queryBytesMName},
+			}),
+		},
+	}
+	if ds.Reuse {
+		// select the logs of the dataset
+		steps = append(steps, model.Scenario{
+			Name: solaris.SelectLogsName,
+			Config: model.ToScenarioConfig(&solaris.SelectLogsCfg{
+				Condition: datasetCondition(ds.Name),
+				LogSet:    datasetLogSet,
+			}),
+		})
+	}
+	steps = append(steps,
+		// append to 'concurrentLogs' number of logs in parallel, each appender adds 100 messages in parallel
+		model.Scenario{
+			Name: runner.RepeatRunName,
+			Config: model.ToScenarioConfig(&runner.RepeatCfg{
+				Count:    concurrentLogs,
+				Executor: runner.ParallelRunName,
+				Action: model.Scenario{
+					Name: runner.SequenceRunName,
+					Config: model.ToScenarioConfig(&runner.SequenceCfg{
+						Steps: logSteps,
 					}),
 				},
-			},
+			}),
+		},
+		// trace metrics
+		model.Scenario{
+			Name: runner.MetricsFixRunName,
+			Config: model.ToScenarioConfig(&runner.MetricsFixCfg{
+				Metrics: []string{appendMetricName, queryMetricName, appendLatencyMName, queryLatencyMName, appendMsgsMName, appendBytesMName, queryMsgsMName, queryBytesMName,
+					appendErrorsMName, queryErrorsMName},
+			}),
+		})
+	return &model.Scenario{
+		Name: runner.SequenceRunName,
+		Config: model.ToScenarioConfig(&runner.SequenceCfg{
+			Steps: steps,
 		}),
 	}
 }
 
-func writeConcurrently(writersToLog, appendsToLog, batchSize, msgSize int, toMName, msgsRateMName, bytesRateMName string) model.Scenario {
+func writeConcurrently(writersToLog, appendsToLog, batchSize, msgSize int, toMName, latencyMName, msgsRateMName, bytesRateMName, errorsMName string) model.Scenario {
 	return model.Scenario{
 		Name: runner.RepeatRunName,
 		Config: model.ToScenarioConfig(&runner.RepeatCfg{
@@ -348,15 +525,17 @@ func writeConcurrently(writersToLog, appendsToLog, batchSize, msgSize int, toMNa
 					BatchSize:           batchSize,
 					Number:              appendsToLog,
 					TimeoutMetricName:   toMName,
+					LatencyMetricName:   latencyMName,
 					MsgsRateMetricName:  msgsRateMName,
 					BytesRateMetricName: bytesRateMName,
+					CallCfg:             solaris.CallCfg{ErrorCodesMetricName: errorsMName},
 				}),
 			},
 		}),
 	}
 }
 
-func readConcurrently(seqReadMode bool, logReaders, queryStep, queriesNumber int, toMetricName, queryMsgsMName, queryBytesMName string) model.Scenario {
+func readConcurrently(seqReadMode bool, logReaders, queryStep, queriesNumber int, toMetricName, latencyMetricName, queryMsgsMName, queryBytesMName, queryErrorsMName string) model.Scenario {
 	var readMode string
 	if seqReadMode {
 		readMode = solaris.SeqQueryMsgsRunName
@@ -375,10 +554,165 @@ func readConcurrently(seqReadMode bool, logReaders, queryStep, queriesNumber int
 					Step:                int64(queryStep),
 					Number:              queriesNumber,
 					TimeoutMetricName:   toMetricName,
+					LatencyMetricName:   latencyMetricName,
 					MsgsRateMetricName:  queryMsgsMName,
 					BytesRateMetricName: queryBytesMName,
+					CallCfg:             solaris.CallCfg{ErrorCodesMetricName: queryErrorsMName},
 				}),
 			},
 		}),
 	}
 }
+
+// createLogsConcurrently creates the logs by the concurrent creators, the
+// remainder of the logs is spread over the first creators
+func createLogsConcurrently(logs, creators int, tags solaris.TagsCfg) model.Scenario {
+	createLogs := func(count, number int) model.Scenario {
+		return model.Scenario{
+			Name: runner.RepeatRunName,
+			Config: model.ToScenarioConfig(&runner.RepeatCfg{
+				Count:    count,
+				Executor: runner.ParallelRunName,
+				Action: model.Scenario{
+					Name: solaris.CreateLogsName,
+					Config: model.ToScenarioConfig(&solaris.CreateLogsCfg{
+						Number:             number,
+						Tags:               tags,
+						TimeoutMetricName:  createLogsToMetricName,
+						LatencyMetricName:  createLogsLatencyMetricName,
+						LogsRateMetricName: createLogsPerSecMetricName,
+						CallCfg:            solaris.CallCfg{ErrorCodesMetricName: createLogsErrorsMetricName},
+					}),
+				},
+			}),
+		}
+	}
+	share, rem := logs/creators, logs%creators
+	switch {
+	case rem == 0:
+		return createLogs(creators, share)
+	case share == 0:
+		return createLogs(rem, 1)
+	}
+	return model.Scenario{
+		Name: runner.ParallelRunName,
+		Config: model.ToScenarioConfig(&runner.ParallelCfg{
+			Steps: []model.Scenario{createLogs(rem, share+1), createLogs(creators-rem, share)},
+		}),
+	}
+}
+
+func buildLogsMetaTests(cfg *LogsMetaCfg) *model.Config {
+	return &model.Config{
+		Log: model.LoggingConfig{Level: defLogLevel},
+		Tests: []model.Test{
+			*logsMetaTest(defaultEnvRunID, defaultAddress, defaultEnvVarAddress, cfg),
+		},
+	}
+}
+
+// logsMetaTest creates the logs with the generated tags, then queries the
+// logs by the tags conditions, updates the tags of the tenth of the logs and
+// deletes all the created logs. The logs are tagged by the cluster node ID,
+// so the nodes of the run and the other runs do not touch the node logs.
+func logsMetaTest(runID, svcAddress, envVarAddress string, cfg *LogsMetaCfg) *model.Test {
+	creators := max(cfg.Creators, 1)
+	metaCondition := fmt.Sprintf("tag('logName') = '%s' AND tag('%s') = '${nodeID}'", metaLogName, metaNodeTag)
+	var conditions []string
+	if cfg.TagsPerLog > 0 {
+		conditions = append(conditions, fmt.Sprintf("%s AND tag('tag{{int(0, %d)}}') = 'val{{int(0, %d)}}'",
+			metaCondition, cfg.TagsPerLog-1, max(cfg.Cardinality, 1)-1))
+	}
+	tags := solaris.TagsCfg{
+		Static:      map[string]string{"logName": metaLogName, metaNodeTag: "${nodeID}"},
+		Count:       cfg.TagsPerLog,
+		Cardinality: cfg.Cardinality,
+	}
+	metrics := map[runner.MetricsType][]string{
+		runner.DURATION:  {createLogsToMetricName, queryLogsToMetricName, updateLogsToMetricName},
+		runner.HISTOGRAM: {createLogsLatencyMetricName, queryLogsLatencyMetricName, updateLogsLatencyMetricName},
+		runner.RPS:       {createLogsPerSecMetricName, queryLogsPerSecMetricName, updateLogsPerSecMetricName},
+		runner.COUNTERS:  {createLogsErrorsMetricName, queryLogsErrorsMetricName, updateLogsErrorsMetricName},
+	}
+	scenario := &model.Scenario{
+		Name: runner.SequenceRunName,
+		Config: model.ToScenarioConfig(&runner.SequenceCfg{
+			Steps: []model.Scenario{
+				// connect to solaris
+				{
+					Name: solaris.ConnectName,
+					Config: model.ToScenarioConfig(&solaris.ConnectCfg{
+						Address:       svcAddress,
+						EnvVarAddress: envVarAddress,
+					}),
+				},
+				// init metrics
+				{
+					Name: runner.MetricsCreateRunName,
+					Config: model.ToScenarioConfig(&runner.MetricsCreateCfg{
+						Metrics: metrics,
+					}),
+				},
+				// create 'logs' logs by 'creators' concurrent creators
+				createLogsConcurrently(cfg.Logs, creators, tags),
+				// query logs by 'readers' concurrent readers
+				{
+					Name: runner.RepeatRunName,
+					Config: model.ToScenarioConfig(&runner.RepeatCfg{
+						Count:    max(cfg.Readers, 1),
+						Executor: runner.ParallelRunName,
+						Action: model.Scenario{
+							Name: solaris.QueryLogsName,
+							Config: model.ToScenarioConfig(&solaris.QueryLogsCfg{
+								Conditions:         conditions,
+								Step:               defaultQueryLogsStep,
+								Pages:              -1,
+								Number:             cfg.Queries,
+								TimeoutMetricName:  queryLogsToMetricName,
+								LatencyMetricName:  queryLogsLatencyMetricName,
+								LogsRateMetricName: queryLogsPerSecMetricName,
+								CallCfg:            solaris.CallCfg{ErrorCodesMetricName: queryLogsErrorsMetricName},
+							}),
+						},
+					}),
+				},
+				// update tags of the tenth of the logs
+				{
+					Name: solaris.UpdateLogsName,
+					Config: model.ToScenarioConfig(&solaris.UpdateLogsCfg{
+						Condition:          metaCondition,
+						Number:             max(cfg.Logs/10, 1),
+						Step:               defaultQueryLogsStep,
+						Tags:               solaris.TagsCfg{Count: cfg.TagsPerLog, Cardinality: cfg.Cardinality},
+						TimeoutMetricName:  updateLogsToMetricName,
+						LatencyMetricName:  updateLogsLatencyMetricName,
+						LogsRateMetricName: updateLogsPerSecMetricName,
+						CallCfg:            solaris.CallCfg{ErrorCodesMetricName: updateLogsErrorsMetricName},
+					}),
+				},
+				// delete logs
+				{
+					Name: solaris.DeleteLogName,
+					Config: model.ToScenarioConfig(&solaris.DeleteLogCfg{
+						Condition: metaCondition,
+					}),
+				},
+				// trace metrics
+				{
+					Name: runner.MetricsFixRunName,
+					Config: model.ToScenarioConfig(&runner.MetricsFixCfg{
+						Metrics: []string{createLogsToMetricName, queryLogsToMetricName, updateLogsToMetricName,
+							createLogsLatencyMetricName, queryLogsLatencyMetricName, updateLogsLatencyMetricName,
+							createLogsPerSecMetricName, queryLogsPerSecMetricName, updateLogsPerSecMetricName,
+							createLogsErrorsMetricName, queryLogsErrorsMetricName, updateLogsErrorsMetricName},
+					}),
+				},
+			},
+		}),
+	}
+	return &model.Test{
+		Name: fmt.Sprintf("Create %d logs (by %d creators) with %d tags of %d values, query logs by %d readers %d times each",
+			cfg.Logs, creators, cfg.TagsPerLog, cfg.Cardinality, max(cfg.Readers, 1), cfg.Queries),
+		Scenario: *clusterRun(runID, svcAddress, envVarAddress, scenario, metrics),
+	}
+}
@@ -0,0 +1,334 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/client"
+	"github.com/solarisdb/perftests/pkg/cluster"
+	solarCluster "github.com/solarisdb/perftests/pkg/cluster/solaris"
+	"github.com/solarisdb/perftests/pkg/metrics"
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/perftests/pkg/server/configs"
+	"github.com/solarisdb/solaris/api/gen/solaris/v1"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/solarisdb/solaris/golibs/logging"
+	"google.golang.org/grpc"
+)
+
+type (
+	// CoordinationCfg describes the coordinator/worker run, the coordinator
+	// publishes the test plan to the workers of the run and awaits their
+	// reports, so the tests are changed in one place
+	CoordinationCfg struct {
+		// RunID identifies the run the coordinator and the workers join
+		RunID string
+		// Address is the Solaris address the run is coordinated through
+		Address string
+		// Workers is the number of the workers the coordinator awaits, the
+		// workers joined the run after them do not run the plan
+		Workers int
+		// Timeout limits awaiting of the plan by the worker, 10m by default,
+		// and of the reports by the coordinator, no limit if 0
+		Timeout time.Duration
+		// PollInterval is how often the coordinator checks the workers joined, 5s by default
+		PollInterval time.Duration
+	}
+
+	// CoordinatorReport is the aggregated report of the workers of the run
+	CoordinatorReport struct {
+		RunID  string `json:"runID"`
+		Passed int    `json:"passed"`
+		Failed int    `json:"failed"`
+		// Metrics contains the metrics of the tests merged over the workers,
+		// by the test and the metric names
+		Metrics map[string]map[string]runner.MetricSummary `json:"metrics,omitempty"`
+		// Workers contains the run summaries of the workers by their node IDs
+		Workers map[string]runner.RunSummary `json:"workers"`
+	}
+
+	// coordinationPlan is the plan published to the workers of the run
+	coordinationPlan struct {
+		// Workers is the number of the workers sharing the plan, the worker
+		// runs the plan with the runner.NodeInfo of its index among them
+		Workers int          `json:"workers"`
+		Config  model.Config `json:"config"`
+	}
+)
+
+const (
+	defaultCoordinationPollInterval = 5 * time.Second
+	defaultPlanTimeout              = 10 * time.Minute
+	// reportTimeout limits the reporting of the worker, which is interrupted
+	reportTimeout = 10 * time.Second
+)
+
+// RunCoordinator publishes the tests of the config as the plan of the run,
+// awaits the reports of the workers and writes the aggregated report to
+// the summary path of the config
+func RunCoordinator(ctx context.Context, cfg *model.Config, cc CoordinationCfg) error {
+	_ = setLogLevelByName(cfg.Log.Level)
+	logger := logging.NewLogger("coordinator")
+	if cc.Workers <= 0 {
+		return fmt.Errorf("the number of workers must be positive: %w", errors.ErrInvalid)
+	}
+	if cc.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cc.Timeout)
+		defer cancel()
+	}
+	cl, conn, err := coordinationCluster(ctx, cc)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cl.Delete(context.Background())
+		_ = conn.Close()
+	}()
+
+	// the workers use their own summary and exporter settings
+	plan := coordinationPlan{Workers: cc.Workers, Config: *cfg}
+	plan.Config.Summary, plan.Config.Exporter = nil, nil
+	b, err := json.Marshal(plan)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the plan: %w", err)
+	}
+	if err = cl.PublishPlan(ctx, b); err != nil {
+		return err
+	}
+	logger.Infof("Plan of %d tests is published to run %s, awaiting %d workers", len(cfg.Tests), cc.RunID, cc.Workers)
+
+	nodes, err := awaitWorkers(ctx, cl, cc)
+	if err != nil {
+		return err
+	}
+	report := CoordinatorReport{RunID: cc.RunID, Metrics: make(map[string]map[string]runner.MetricSummary),
+		Workers: make(map[string]runner.RunSummary, len(nodes))}
+	for _, node := range nodes {
+		res, err := node.Result(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get the report of worker %s: %w", node.ID(), err)
+		}
+		var summary runner.RunSummary
+		if err = json.Unmarshal(res, &summary); err != nil {
+			return fmt.Errorf("invalid report of worker %s: %w", node.ID(), err)
+		}
+		report.Workers[node.ID()] = summary
+		report.Passed += summary.Passed
+		report.Failed += summary.Failed
+		for _, ts := range summary.Tests {
+			for name, ms := range ts.Metrics {
+				if err = report.mergeMetric(ts.Name, name, ms); err != nil {
+					logger.Warnf("Metric %s of test %s of worker %s is not merged: %s", name, ts.Name, node.ID(), err.Error())
+				}
+			}
+		}
+	}
+	logReport(logger, report)
+	if report.Failed > 0 {
+		err = fmt.Errorf("%d tests failed on the workers of run %s", report.Failed, cc.RunID)
+	}
+	return saveSummary(cfg, report, err)
+}
+
+// RunWorker joins the run, runs the tests of the published plan merged
+// into the config and reports their summary to the coordinator. The worker
+// runs the plan with the node info of its index among the workers of the
+// run, the worker joined after them does not run the plan.
+func RunWorker(ctx context.Context, cfg *model.Config, cc CoordinationCfg) error {
+	_ = setLogLevelByName(cfg.Log.Level)
+	logger := logging.NewLogger("worker")
+	cl, conn, err := coordinationCluster(ctx, cc)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	node, err := cl.AddNode(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to join run %s: %w", cc.RunID, err)
+	}
+	timeout := cc.Timeout
+	if timeout <= 0 {
+		timeout = defaultPlanTimeout
+	}
+	planCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	logger.Infof("Worker %s joined run %s, awaiting the plan", node.ID(), cc.RunID)
+	b, err := cl.Plan(planCtx)
+	if err != nil {
+		return err
+	}
+	var plan coordinationPlan
+	if err = json.Unmarshal(b, &plan); err != nil {
+		return fmt.Errorf("invalid plan of run %s: %w", cc.RunID, err)
+	}
+	ni, err := workerInfo(planCtx, cl, node, plan.Workers)
+	if err != nil {
+		return err
+	}
+	if err = configs.Merge(cfg, &plan.Config); err != nil {
+		return fmt.Errorf("failed to merge the plan: %w", err)
+	}
+	logger.Infof("Worker %s is %d of %d workers of run %s", node.ID(), ni.Index, ni.Count, cc.RunID)
+
+	summary, err := runTests(runner.WithNodeInfo(ctx, ni), cfg)
+	report, _ := json.Marshal(summary)
+	// the interrupted worker still reports the tests run
+	reportCtx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+	if rErr := node.Finish(reportCtx, report); rErr != nil {
+		logger.Errorf("could not report to the coordinator: %s", rErr.Error())
+		if err == nil {
+			err = rErr
+		}
+	}
+	return saveSummary(cfg, summary, err)
+}
+
+// coordinationCluster returns the cluster the run is coordinated through
+// and its connection, which is closed by the caller. The cluster differs
+// from the cluster of the run tests, which is deleted by them.
+func coordinationCluster(ctx context.Context, cc CoordinationCfg) (cluster.Cluster, *grpc.ClientConn, error) {
+	if len(cc.RunID) == 0 {
+		return nil, nil, fmt.Errorf("run ID is empty: %w", errors.ErrInvalid)
+	}
+	conn, err := client.Dial(ctx, cc.Address, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to dial to address %s: %w", cc.Address, err)
+	}
+	cl, err := solarCluster.NewCluster(ctx, cc.RunID+".coordination", solaris.NewServiceClient(conn))
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("failed to connect to run %s: %w", cc.RunID, err)
+	}
+	return cl, conn, nil
+}
+
+// workerInfo returns the node info of the worker, which is its index among
+// the workers of the run by the order they joined it, the worker joined
+// after the workers of the run is rejected
+func workerInfo(ctx context.Context, cl cluster.Cluster, node cluster.Node, workers int) (runner.NodeInfo, error) {
+	nodes, err := cl.Nodes(ctx)
+	if err != nil {
+		return runner.NodeInfo{}, err
+	}
+	for i, n := range nodes {
+		if n.ID() != node.ID() {
+			continue
+		}
+		if i >= workers {
+			return runner.NodeInfo{}, fmt.Errorf("worker %s is %d of %d workers of the run: %w", node.ID(), i, workers, errors.ErrExhausted)
+		}
+		return runner.NodeInfo{ID: node.ID(), Index: i, Count: workers}, nil
+	}
+	return runner.NodeInfo{}, fmt.Errorf("worker %s is not registered: %w", node.ID(), errors.ErrNotExist)
+}
+
+// awaitWorkers returns the first Workers nodes joined the run
+func awaitWorkers(ctx context.Context, cl cluster.Cluster, cc CoordinationCfg) ([]cluster.Node, error) {
+	pollInterval := cc.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultCoordinationPollInterval
+	}
+	for {
+		nodes, err := cl.Nodes(ctx)
+		if err != nil && ctx.Err() == nil {
+			return nil, err
+		}
+		if len(nodes) >= cc.Workers {
+			return nodes[:cc.Workers], nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%d of %d workers joined run %s: %w", len(nodes), cc.Workers, cc.RunID, ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// mergeMetric merges the metric of the test of the worker into the report
+func (r *CoordinatorReport) mergeMetric(test, name string, ms runner.MetricSummary) error {
+	testMetrics, ok := r.Metrics[test]
+	if !ok {
+		testMetrics = make(map[string]runner.MetricSummary)
+		r.Metrics[test] = testMetrics
+	}
+	merged, ok := testMetrics[name]
+	if !ok {
+		testMetrics[name] = ms
+		return nil
+	}
+	if merged.Type != ms.Type {
+		return fmt.Errorf("metric type %s differs from %s: %w", ms.Type, merged.Type, errors.ErrInvalid)
+	}
+	var res any
+	var err error
+	switch ms.Type {
+	case runner.DURATION:
+		res, err = mergeResult[metrics.DurationMetricResult](merged.Result, ms.Result)
+	case runner.RPS:
+		res, err = mergeResult[metrics.RateMetricResult](merged.Result, ms.Result)
+	case runner.INT:
+		res, err = mergeResult[metrics.IntMetricResult](merged.Result, ms.Result)
+	case runner.HISTOGRAM:
+		res, err = mergeResult[metrics.HistogramMetricResult](merged.Result, ms.Result)
+	case runner.GAUGE:
+		res, err = mergeResult[metrics.GaugeMetricResult](merged.Result, ms.Result)
+	case runner.COUNTERS:
+		res, err = mergeResult[metrics.CountersMetricResult](merged.Result, ms.Result)
+	case runner.STRING:
+		res, err = mergeResult[metrics.StringMetricResult](merged.Result, ms.Result)
+	default:
+		return fmt.Errorf("unknown metric type %s: %w", ms.Type, errors.ErrInvalid)
+	}
+	if err != nil {
+		return err
+	}
+	testMetrics[name] = runner.MetricSummary{Type: ms.Type, Result: res}
+	return nil
+}
+
+// mergeResult returns the metric results decoded from the reports merged
+func mergeResult[T interface{ Merge(T) T }](to, from any) (any, error) {
+	var t, f T
+	if err := remarshal(to, &t); err != nil {
+		return nil, err
+	}
+	if err := remarshal(from, &f); err != nil {
+		return nil, err
+	}
+	return t.Merge(f), nil
+}
+
+// remarshal decodes the value decoded from JSON into the typed one
+func remarshal(v, typed any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, typed)
+}
+
+func logReport(logger logging.Logger, report CoordinatorReport) {
+	ids := make([]string, 0, len(report.Workers))
+	for id := range report.Workers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		summary := report.Workers[id]
+		logger.Infof("Worker %s: %d passed, %d failed", id, summary.Passed, summary.Failed)
+		for _, ts := range summary.Tests {
+			if ts.Status == runner.TestFailed {
+				logger.Infof("  %s failed: %v", ts.Name, ts.Errors)
+			}
+		}
+	}
+	logger.Infof("Run %s: %d workers, %d passed, %d failed", report.RunID, len(report.Workers), report.Passed, report.Failed)
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/solarisdb/perftests/pkg/model"
+	"github.com/solarisdb/perftests/pkg/runner"
+	"github.com/solarisdb/solaris/golibs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoordinator(t *testing.T) {
+	cc := CoordinationCfg{RunID: "test-run", Address: "inmem://test-coordinator", Workers: 2,
+		Timeout: 20 * time.Second, PollInterval: 500 * time.Millisecond}
+	summaryPath := filepath.Join(t.TempDir(), "summary.json")
+	cfg := &model.Config{
+		Log:     model.LoggingConfig{Level: "info"},
+		Summary: &model.SummaryConfig{Path: summaryPath},
+		Tests:   []model.Test{sharedPauseTest()},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- RunCoordinator(context.Background(), cfg, cc)
+	}()
+	// the plan is published before the workers join
+	time.Sleep(100 * time.Millisecond)
+	workerErrCh := make(chan error, 1)
+	go func() {
+		workerErrCh <- RunWorker(context.Background(), &model.Config{Log: cfg.Log}, cc)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	assert.NoError(t, RunWorker(context.Background(), &model.Config{Log: cfg.Log}, cc))
+	assert.NoError(t, <-workerErrCh)
+	// the extra worker does not run the plan
+	err := RunWorker(context.Background(), &model.Config{Log: cfg.Log}, cc)
+	assert.ErrorIs(t, err, errors.ErrExhausted)
+	assert.NoError(t, <-errCh)
+
+	b, err := os.ReadFile(summaryPath)
+	assert.NoError(t, err)
+	var report CoordinatorReport
+	assert.NoError(t, json.Unmarshal(b, &report))
+	assert.Equal(t, "test-run", report.RunID)
+	assert.Equal(t, 2, report.Passed)
+	assert.Equal(t, 0, report.Failed)
+	assert.Len(t, report.Workers, 2)
+	// the workers share the pauses, so the merged metric counts all of them
+	ms := report.Metrics["pause"]["pauseTO"]
+	assert.Equal(t, runner.DURATION, ms.Type)
+	assert.Equal(t, float64(10), ms.Result.(map[string]any)["total"])
+
+	_, _, err = coordinationCluster(context.Background(), CoordinationCfg{Address: cc.Address})
+	assert.Error(t, err)
+}
+
+// sharedPauseTest returns the test of the 10 pauses shared by the workers
+func sharedPauseTest() model.Test {
+	return model.Test{
+		Name: "pause",
+		Scenario: model.Scenario{
+			Name: runner.SequenceRunName,
+			Config: model.ToScenarioConfig(&runner.SequenceCfg{
+				Steps: []model.Scenario{
+					{
+						Name: runner.MetricsCreateRunName,
+						Config: model.ToScenarioConfig(&runner.MetricsCreateCfg{
+							Metrics: map[runner.MetricsType][]string{runner.DURATION: {"pauseTO"}},
+						}),
+					},
+					{
+						Name: runner.RepeatRunName,
+						Config: model.ToScenarioConfig(map[string]any{
+							"count":    "${share(10)}",
+							"executor": runner.SequenceRunName,
+							"action": model.Scenario{
+								Name: runner.SequenceRunName,
+								Config: model.ToScenarioConfig(&runner.SequenceCfg{
+									StepTimeoutMetric: "pauseTO",
+									Steps: []model.Scenario{{
+										Name:   runner.PauseRunName,
+										Config: model.ToScenarioConfig(&runner.PauseCfg{Value: "10ms"}),
+									}},
+								}),
+							},
+						}),
+					},
+					{
+						Name:   runner.MetricsFixRunName,
+						Config: model.ToScenarioConfig(&runner.MetricsFixCfg{Metrics: []string{"pauseTO"}}),
+					},
+				},
+			}),
+		},
+	}
+}
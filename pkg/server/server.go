@@ -2,7 +2,10 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/logrange/linker"
@@ -17,6 +20,30 @@ import (
 
 // Run is an entry point of the server
 func Run(ctx context.Context, cfg *model.Config) error {
+	summary, err := runTests(ctx, cfg)
+	return saveSummary(cfg, summary, err)
+}
+
+// saveSummary writes the summary to the path of the config if it is set,
+// the run error is returned or the write error if the run is succeeded
+func saveSummary(cfg *model.Config, summary any, err error) error {
+	if cfg.Summary == nil || len(cfg.Summary.Path) == 0 {
+		return err
+	}
+	logger := logging.NewLogger("runner")
+	if wErr := writeSummary(cfg.Summary.Path, summary); wErr != nil {
+		logger.Errorf("could not write the summary: %s", wErr.Error())
+		if err == nil {
+			err = wErr
+		}
+	} else {
+		logger.Infof("Summary is written to %s", cfg.Summary.Path)
+	}
+	return err
+}
+
+// runTests runs the tests of the config and returns their summary
+func runTests(ctx context.Context, cfg *model.Config) (runner.RunSummary, error) {
 	_ = setLogLevelByName(cfg.Log.Level)
 	logger := logging.NewLogger("runner")
 	logger.Infof("Test started (%s)", version.BuildVersionString())
@@ -28,16 +55,23 @@ func Run(ctx context.Context, cfg *model.Config) error {
 		linker.Component{Value: cfg},
 		linker.Component{Value: logger},
 		linker.Component{Value: runner.NewRegistry()},
+		linker.Component{Value: runner.NewMetricsExporter()},
 		linker.Component{Value: runner.NewSequenceExecutor()},
 		linker.Component{Value: runner.NewRepeatExecutor()},
 		linker.Component{Value: runner.NewParallelExecutor()},
 		linker.Component{Value: runner.NewPauseExecutor()},
 		linker.Component{Value: runner.NewAwaitExecutor()},
+		linker.Component{Value: runner.NewTriggerCreateExecutor()},
+		linker.Component{Value: runner.NewTriggerFireExecutor()},
 		linker.Component{Value: runner.NewErrorExecutor()},
 		linker.Component{Value: runner.NewMetricsCreateExecutor()},
 		linker.Component{Value: runner.NewMetricsFixExecutor()},
 		linker.Component{Value: runner.NewDelayExecutor()},
 		linker.Component{Value: runner.NewWeightedExecutor()},
+		linker.Component{Value: runner.NewAssertExecutor()},
+		linker.Component{Value: runner.NewRateExecutor()},
+		linker.Component{Value: runner.NewStagesExecutor()},
+		linker.Component{Value: runner.NewLoopExecutor()},
 
 		linker.Component{Value: testsRunner},
 
@@ -46,19 +80,41 @@ func Run(ctx context.Context, cfg *model.Config) error {
 		linker.Component{Value: solaris.NewAppendMsgExecutor()},
 		linker.Component{Value: solaris.NewCreateLogExecutor()},
 		linker.Component{Value: solaris.NewDeleteLogExecutor()},
+		linker.Component{Value: solaris.NewLogSetExecutor()},
+		linker.Component{Value: solaris.NewSelectLogsExecutor()},
 		linker.Component{Value: solaris.NewSeqQueryMsgsExecutor()},
+		linker.Component{Value: solaris.NewVerifyMsgsExecutor()},
+		linker.Component{Value: solaris.NewTailMsgsExecutor()},
+		linker.Component{Value: solaris.NewQueryFilteredExecutor()},
+		linker.Component{Value: solaris.NewCreateLogsExecutor()},
+		linker.Component{Value: solaris.NewQueryLogsExecutor()},
+		linker.Component{Value: solaris.NewUpdateLogsExecutor()},
 		linker.Component{Value: solaris.NewRandQueryMsgsExecutor()},
 
 		//cluster
 		linker.Component{Value: cluster.NewConnectExecutor()},
 		linker.Component{Value: cluster.NewFinishExecutor()},
+		linker.Component{Value: cluster.NewBarrierExecutor()},
 		linker.Component{Value: cluster.NewDeleteClusterExecutor()},
 	)
 	inj.Init(ctx)
-	<-testsRunner.Run(ctx)
+	err := <-testsRunner.Run(ctx)
 	logger.Infof("Stopping ...")
 	inj.Shutdown()
+	return testsRunner.Summary, err
+}
 
+func writeSummary(path string, summary any) error {
+	b, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the summary: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create the summary dir: %w", err)
+	}
+	if err = os.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("failed to write the summary file %s: %w", path, err)
+	}
 	return nil
 }
 
@@ -11,8 +11,10 @@ import (
 )
 
 func Test_FRun(t *testing.T) {
-	t.SkipNow()
-	cfg := testCfg()
+	t.Setenv(defaultEnvVarAddress, "inmem://test-frun")
+	t.Setenv(defaultEnvRunID, "test-frun")
+	cfg := BuildConfig(Append, &AppendCfg{ConcurrentLogs: 2, LogSize: 64 * 1024, WritersForOneLog: 2, BatchSize: 10, MsgSize: 256})
+	cfg.Tests = append(cfg.Tests, testCfg().Tests...)
 	err := Run(context.Background(), cfg)
 	assert.NoError(t, err)
 }
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+
+	"github.com/solarisdb/solaris/golibs/errors"
+)
+
+// Distribution returns the random values of the distribution defined by one
+// of the functions: "normal(mean, stdDev)", "uniform(min, max)" or "constant(value)"
+type Distribution func() int64
+
+var (
+	normalDistRegexp   = regexp.MustCompile(`normal\s*\(\s*(\d+)\s*,\s*(\d+)\s*\)`)
+	uniformDistRegexp  = regexp.MustCompile(`uniform\s*\(\s*(\d+)\s*,\s*(\d+)\s*\)`)
+	constantDistRegexp = regexp.MustCompile(`constant\s*\(\s*(\d+)\s*\)`)
+)
+
+// ParseDistribution parses the distribution function
+func ParseDistribution(function string) (Distribution, error) {
+	input := []byte(function)
+	if normalDistRegexp.Match(input) {
+		params := normalDistRegexp.FindSubmatch(input)
+
+		mean, err := strconv.Atoi(string(params[1]))
+		if err != nil {
+			return nil, err
+		}
+		stdDev, err := strconv.Atoi(string(params[2]))
+		if err != nil {
+			return nil, err
+		}
+		return func() int64 {
+			return int64(rand.NormFloat64()*float64(stdDev) + float64(mean))
+		}, nil
+	} else if uniformDistRegexp.Match(input) {
+		params := uniformDistRegexp.FindSubmatch(input)
+
+		min, err := strconv.Atoi(string(params[1]))
+		if err != nil {
+			return nil, err
+		}
+		max, err := strconv.Atoi(string(params[2]))
+		if err != nil {
+			return nil, err
+		}
+		if min >= max {
+			return nil, fmt.Errorf("max should be greater than min: %w", errors.ErrInvalid)
+		}
+		return func() int64 {
+			return rand.Int63n(int64(max-min)) + int64(min)
+		}, nil
+	} else if constantDistRegexp.Match(input) {
+		params := constantDistRegexp.FindSubmatch(input)
+		value, err := strconv.Atoi(string(params[1]))
+		if err != nil {
+			return nil, err
+		}
+		return func() int64 {
+			return int64(value)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown distribution function: %s: %w", function, errors.ErrInvalid)
+}